@@ -0,0 +1,214 @@
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"unicode/utf8"
+
+	"go.followtheprocess.codes/hue"
+)
+
+const (
+	hexDumpWidth       = 16 // Number of bytes shown on each row of a hex dump
+	hexDumpContextRows = 1  // Number of identical rows shown around each differing row
+)
+
+const (
+	styleHexRemoved          = hue.Red
+	styleHexAdded            = hue.Green
+	styleHexRemovedHighlight = hue.Black | hue.Bold | hue.RedBackground
+	styleHexAddedHighlight   = hue.Black | hue.Bold | hue.GreenBackground
+)
+
+// isBinary reports whether data should be treated as binary rather than text,
+// that is it is not valid UTF-8 or it contains a NUL byte.
+func isBinary(data []byte) bool {
+	return !utf8.Valid(data) || bytes.IndexByte(data, 0) != -1
+}
+
+// firstDifference returns the offset of the first byte that differs between
+// a and b, including where one is a prefix of the other, or -1 if they are equal.
+func firstDifference(a, b []byte) int {
+	for i := range min(len(a), len(b)) {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+
+	if len(a) != len(b) {
+		return min(len(a), len(b))
+	}
+
+	return -1
+}
+
+// hexDiff renders an aligned, hexdump -C style comparison of got and want showing
+// only the rows that differ and hexDumpContextRows identical rows around them.
+//
+// Identical rows are shown once, differing rows are shown as a "-" want row and
+// a "+" got row followed by a row of carets marking the bytes that differ. Skipped
+// rows are marked with a "*".
+//
+// If styled is true, the differing bytes are highlighted using the same colours
+// as the unified diff. If maxLines is greater than 0, the dump is cut after that
+// many lines and the remainder summarised.
+func hexDiff(got, want []byte, styled bool, maxLines int) []byte {
+	first := firstDifference(got, want)
+	if first == -1 {
+		return nil
+	}
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "Got:\t%s\n", plural(len(got), "byte"))
+	fmt.Fprintf(buf, "Wanted:\t%s\n\n", plural(len(want), "byte"))
+	fmt.Fprintf(buf, "First difference at offset 0x%08x (%d)\n\n", first, first)
+
+	rows := (max(len(got), len(want)) + hexDumpWidth - 1) / hexDumpWidth
+
+	differs := make([]bool, rows)
+	for row := range rows {
+		differs[row] = rowDiffers(got, want, row)
+	}
+
+	var lines [][]byte
+
+	last := -1 // Last row shown
+
+	for row := range rows {
+		if !nearDifference(differs, row) {
+			continue
+		}
+
+		if last != -1 && row != last+1 {
+			lines = append(lines, []byte("  *\n"))
+		}
+
+		last = row
+
+		if !differs[row] {
+			lines = append(lines, hexRow([]byte("  "), row, want, got, 0))
+			continue
+		}
+
+		removed, added := []byte("- "), []byte("+ ")
+		removedHighlight, addedHighlight := hue.Style(0), hue.Style(0)
+
+		if styled {
+			removed = styleHexRemoved.AppendText(nil, removed)
+			added = styleHexAdded.AppendText(nil, added)
+			removedHighlight, addedHighlight = styleHexRemovedHighlight, styleHexAddedHighlight
+		}
+
+		lines = append(lines, hexRow(removed, row, want, got, removedHighlight))
+		lines = append(lines, hexRow(added, row, got, want, addedHighlight))
+		lines = append(lines, caretRow(row, got, want))
+	}
+
+	if last != rows-1 {
+		lines = append(lines, []byte("  *\n"))
+	}
+
+	if maxLines > 0 && len(lines) > maxLines {
+		omitted := len(lines) - maxLines
+		lines = append(lines[:maxLines], fmt.Appendf(nil, "\n... %s omitted\n", plural(omitted, "more line")))
+	}
+
+	for _, line := range lines {
+		buf.Write(line)
+	}
+
+	return buf.Bytes()
+}
+
+// rowDiffers reports whether any byte in the given row differs between a and b.
+func rowDiffers(a, b []byte, row int) bool {
+	for i := row * hexDumpWidth; i < (row+1)*hexDumpWidth; i++ {
+		if byteDiffers(a, b, i) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// byteDiffers reports whether the byte at offset i differs between a and b, a byte
+// present on one side but not the other counts as differing.
+func byteDiffers(a, b []byte, i int) bool {
+	inA, inB := i < len(a), i < len(b)
+	if inA != inB {
+		return true
+	}
+
+	return inA && a[i] != b[i]
+}
+
+// nearDifference reports whether row is within hexDumpContextRows of a differing row.
+func nearDifference(differs []bool, row int) bool {
+	for r := max(0, row-hexDumpContextRows); r <= min(len(differs)-1, row+hexDumpContextRows); r++ {
+		if differs[r] {
+			return true
+		}
+	}
+
+	return false
+}
+
+// hexRow appends a single hex dump row of data to prefix and returns the result,
+// highlighting any bytes that differ from other with highlight, unless highlight is 0.
+func hexRow(prefix []byte, row int, data, other []byte, highlight hue.Style) []byte {
+	start := row * hexDumpWidth
+	dst := fmt.Appendf(prefix, "%08x  ", start)
+
+	for i := start; i < start+hexDumpWidth; i++ {
+		cell := "  "
+		if i < len(data) {
+			cell = fmt.Sprintf("%02x", data[i])
+		}
+
+		if highlight != 0 && byteDiffers(data, other, i) {
+			dst = highlight.AppendString(dst, cell)
+		} else {
+			dst = append(dst, cell...)
+		}
+
+		dst = append(dst, ' ')
+
+		if i == start+hexDumpWidth/2-1 {
+			dst = append(dst, ' ')
+		}
+	}
+
+	dst = append(dst, " |"...)
+
+	for i := start; i < min(start+hexDumpWidth, len(data)); i++ {
+		char := data[i]
+		if char < ' ' || char > '~' {
+			char = '.'
+		}
+
+		dst = append(dst, char)
+	}
+
+	return append(dst, "|\n"...)
+}
+
+// caretRow returns a row of carets, aligned with the hex columns of a row
+// rendered by hexRow, marking each byte that differs between a and b.
+func caretRow(row int, a, b []byte) []byte {
+	start := row * hexDumpWidth
+	line := []byte("            ") // Aligns with the prefix, offset and padding of hexRow
+
+	for i := start; i < start+hexDumpWidth; i++ {
+		if byteDiffers(a, b, i) {
+			line = append(line, "^^ "...)
+		} else {
+			line = append(line, "   "...)
+		}
+
+		if i == start+hexDumpWidth/2-1 {
+			line = append(line, ' ')
+		}
+	}
+
+	return append(bytes.TrimRight(line, " "), '\n')
+}
@@ -4,50 +4,119 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"path"
+	"path/filepath"
+	"reflect"
 	"strings"
+	"testing"
+	"text/tabwriter"
 	"unicode/utf8"
 )
 
 const (
 	defaultFloatEqualityThreshold = 1e-8
+	defaultDiffContextLines       = 3
 )
 
 // config holds test-specific configuration including additional context
 // and how the caller wants this library to behave.
 type config struct {
-	title                  string  // Title of the test, shown as a header in the failure log
-	context                string  // Additional context passed by the caller
-	reason                 string  // Concise reason why the test has failed, only used sparingly and not in a user option
-	floatEqualityThreshold float64 // The difference threshold below which two floats are considered equal
+	title                  string                     // Title of the test, shown as a header in the failure log
+	context                []func() string            // Additional context passed by the caller, computed on failure
+	fields                 []field                    // Structured key value context passed by the caller with With
+	hints                  []string                   // Suggestions for how to fix the failure, shown after the reason
+	formatters             []typeFormatter            // Per type formatters set with FormatWith, later ones take precedence
+	goSyntax               bool                       // Whether values are formatted as Go syntax with %#v
+	reason                 string                     // Concise reason why the test has failed, only used sparingly and not in a user option
+	floatEqualityThreshold float64                    // The difference threshold below which two floats are considered equal
+	relativeTolerance      float64                    // The relative difference below which two floats are considered equal
+	ulpTolerance           int                        // The number of ULPs within which two floats are considered equal
+	floatTolerance         floatTolerance             // Which of the float tolerances above are used to compare floats
+	nanEqual               bool                       // Whether NaN is considered equal to NaN when comparing floats
+	diffContextLines       int                        // Number of unchanged lines shown around each change in a diff
+	maxDiffHunks           int                        // Maximum number of diff hunks to show, 0 means no limit
+	maxDiffLines           int                        // Maximum number of diff lines to show, 0 means no limit
+	saveDiff               string                     // If set, the full diff is written to this file in the test's artifact dir
+	normalizers            []func(data []byte) []byte // Applied in order to both sides of a diff before comparing
+	ignorePaths            []string                   // Glob patterns of paths to skip when comparing file systems
 }
 
-// defaultConfig returns a default configuration.
-func defaultConfig() config {
-	return config{
+// defaultConfig returns a default configuration with any default options installed
+// by [SetDefaults] and [Defaults] for tb applied, tb may be nil.
+func defaultConfig(tb testing.TB) config {
+	cfg := config{
 		floatEqualityThreshold: defaultFloatEqualityThreshold,
+		diffContextLines:       defaultDiffContextLines,
 	}
+
+	applyDefaults(tb, &cfg)
+
+	return cfg
 }
 
 // failure represents a test failure, including any set config.
 type failure[T any] struct {
-	got  T      // The actual value
-	want T      // Expected value
-	cfg  config // Test config
+	got       T         // The actual value
+	want      T         // Expected value
+	cfg       config    // Test config
+	sections  []Section // Any additional detail, shown after got and want
+	diffLines bool      // Whether multi-line strings are shown as a diff whatever their length
+	noValues  bool      // Whether got and want are left out, for failures that aren't a comparison
+}
+
+// Section is an additional labelled block of detail in a failure, shown after got and want.
+//
+// See [Fail] for how to use it in custom assertions.
+type Section struct {
+	Label string // Heading shown above the section e.g. "Explanation"
+	Body  string // Content of the section, may span multiple lines
 }
 
 // String implements [fmt.Stringer] for failure, allowing it to print itself in the test log.
+//
+// Long values are truncated around their first difference, and long multi-line strings
+// are shown as a unified diff instead, as are any multi-line strings if diffLines is set.
 func (f failure[T]) String() string {
-	s := &strings.Builder{}
-	f.cfg.writeHeader(s)
+	body := &strings.Builder{}
 
-	fmt.Fprintf(s, "Got:\t%+v\n", f.got)
-	fmt.Fprintf(s, "Wanted:\t%+v\n", f.want)
+	if !f.noValues {
+		if diff := multiLineDiff(f.got, f.want, f.cfg, f.diffLines); diff != nil {
+			body.Write(diff)
+		} else {
+			got, want := f.cfg.formatPair(f.got, f.want)
+			writeValues(body, got, want)
+		}
+	}
+
+	for _, section := range f.sections {
+		fmt.Fprintf(body, "\n%s:\n%s", section.Label, section.Body)
+
+		if !strings.HasSuffix(section.Body, "\n") {
+			body.WriteByte('\n')
+		}
+	}
 
-	f.cfg.writeFooter(s)
+	f.cfg.writeFooter(body)
+
+	text := body.String()
+	if f.noValues {
+		// There's nothing above the sections and footer to separate them from
+		text = strings.TrimPrefix(text, "\n")
+	}
+
+	s := &strings.Builder{}
+	f.cfg.writeHeader(s)
+	s.WriteString(text)
 
 	return s.String()
 }
 
+// field is a key value pair of context, set by [With].
+type field struct {
+	value any    // The value, formatted when the test fails
+	key   string // The name of the value
+}
+
 // writeHeader writes the title block (leading blank line, title, underline, blank line)
 // to s. The underline is sized by rune count so multi-byte titles align correctly.
 func (c config) writeHeader(s *strings.Builder) {
@@ -58,15 +127,44 @@ func (c config) writeHeader(s *strings.Builder) {
 	s.WriteString("\n\n")
 }
 
-// writeFooter writes any optional context and reason lines to s.
+// writeFooter writes any optional context, key value fields, reason and hint lines to s,
+// calling any functions set by [ContextFunc] to compute the context.
 func (c config) writeFooter(s *strings.Builder) {
-	if c.context != "" {
-		fmt.Fprintf(s, "\n(%s)\n", c.context)
+	if len(c.context) != 0 {
+		s.WriteByte('\n')
+	}
+
+	for _, fn := range c.context {
+		context := strings.TrimSpace(fn())
+		if context == "" {
+			context = "ContextFunc: cannot set context to an empty string"
+		}
+
+		fmt.Fprintf(s, "(%s)\n", context)
+	}
+
+	if len(c.fields) != 0 {
+		s.WriteByte('\n')
+
+		tw := tabwriter.NewWriter(s, 0, 0, 1, ' ', 0)
+		for _, field := range c.fields {
+			fmt.Fprintf(tw, "%s:\t%s\n", field.key, c.format(field.value))
+		}
+
+		tw.Flush()
 	}
 
 	if c.reason != "" {
 		fmt.Fprintf(s, "\nBecause: %s\n", c.reason)
 	}
+
+	if len(c.hints) != 0 && c.reason == "" {
+		s.WriteByte('\n')
+	}
+
+	for _, hint := range c.hints {
+		fmt.Fprintf(s, "Hint: %s\n", hint)
+	}
 }
 
 // Option is a configuration option for a test.
@@ -93,6 +191,10 @@ func (o option) apply(cfg *config) error {
 // Setting threshold to ±[math.Inf] is an error and will fail the test.
 //
 // The default is 1e-8, a sensible default for most cases.
+//
+// An absolute threshold is meaningless for very large or very small numbers, in which
+// case see [RelativeTolerance], [ULPTolerance] and [AbsOrRelTolerance]. Only one of these
+// tolerances is used at a time, the last one passed wins.
 func FloatEqualityThreshold(threshold float64) Option {
 	f := func(cfg *config) error {
 		if math.IsInf(threshold, 0) {
@@ -100,6 +202,61 @@ func FloatEqualityThreshold(threshold float64) Option {
 		}
 
 		cfg.floatEqualityThreshold = threshold
+		cfg.floatTolerance = toleranceAbsolute
+
+		return nil
+	}
+
+	return option(f)
+}
+
+// RelativeTolerance is an [Option] that compares floating point numbers by their relative
+// difference rather than an absolute threshold, that is two numbers a and b are considered
+// equal if |a - b| <= tolerance * max(|a|, |b|). This setting is only used in [NearlyEqual]
+// and [NotNearlyEqual].
+//
+// This is useful for values where the magnitude varies, e.g. a tolerance of 1e-9 considers
+// numbers equal if they agree to roughly 9 significant figures whether they are around 1e12
+// or 1e-12. Note that no non-zero number is relatively close to 0, see [AbsOrRelTolerance]
+// for comparing values that may be near 0.
+//
+// Setting tolerance to a negative number, ±[math.Inf] or NaN is an error and will fail the test.
+//
+//	test.NearlyEqual(t, 1.0000000001e12, 1e12, test.RelativeTolerance(1e-9)) // Passes
+func RelativeTolerance(tolerance float64) Option {
+	f := func(cfg *config) error {
+		if err := validateTolerance(tolerance); err != nil {
+			return fmt.Errorf("invalid relative tolerance: %w", err)
+		}
+
+		cfg.relativeTolerance = tolerance
+		cfg.floatTolerance = toleranceRelative
+
+		return nil
+	}
+
+	return option(f)
+}
+
+// ULPTolerance is an [Option] that compares floating point numbers by the number of
+// representable floating point values between them, or "units in the last place" (ULPs).
+// Two numbers are considered equal if they are at most ulps apart. This setting is only used
+// in [NearlyEqual] and [NotNearlyEqual].
+//
+// ULPs are counted at the precision of the type being compared, so float32 values are a
+// lot further apart in ULPs than the same values as float64.
+//
+// Setting ulps to a negative number is an error and will fail the test.
+//
+//	test.NearlyEqual(t, math.Nextafter(1, 2), 1.0, test.ULPTolerance(4)) // Passes, 1 ULP apart
+func ULPTolerance(ulps int) Option {
+	f := func(cfg *config) error {
+		if ulps < 0 {
+			return fmt.Errorf("cannot set ULP tolerance to a negative number: %d", ulps)
+		}
+
+		cfg.ulpTolerance = ulps
+		cfg.floatTolerance = toleranceULP
 
 		return nil
 	}
@@ -107,6 +264,69 @@ func FloatEqualityThreshold(threshold float64) Option {
 	return option(f)
 }
 
+// AbsOrRelTolerance is an [Option] that considers floating point numbers equal if they
+// are within either the absolute or the relative tolerance of each other, in the same way as
+// Python's math.isclose, that is |a - b| <= max(relative * max(|a|, |b|), absolute). This setting
+// is only used in [NearlyEqual] and [NotNearlyEqual].
+//
+// The absolute tolerance handles values near 0 where a relative tolerance would be too
+// strict, and the relative tolerance handles values of large magnitude.
+//
+// Setting either tolerance to a negative number, ±[math.Inf] or NaN is an error and will fail the test.
+//
+//	test.NearlyEqual(t, got, want, test.AbsOrRelTolerance(1e-12, 1e-9))
+func AbsOrRelTolerance(absolute, relative float64) Option {
+	f := func(cfg *config) error {
+		if err := validateTolerance(absolute); err != nil {
+			return fmt.Errorf("invalid absolute tolerance: %w", err)
+		}
+
+		if err := validateTolerance(relative); err != nil {
+			return fmt.Errorf("invalid relative tolerance: %w", err)
+		}
+
+		cfg.floatEqualityThreshold = absolute
+		cfg.relativeTolerance = relative
+		cfg.floatTolerance = toleranceAbsOrRel
+
+		return nil
+	}
+
+	return option(f)
+}
+
+// NaNEqual is an [Option] that sets whether NaN is considered equal to NaN when comparing
+// floating point numbers. This setting is only used in [NearlyEqual] and [NotNearlyEqual].
+//
+// By default, and in keeping with the IEEE 754 standard, NaN is not equal to anything, not even
+// itself. This can be inconvenient when NaN is an expected result, in which case pass NaNEqual(true).
+// NaN is never considered equal to a number, whatever the setting.
+//
+//	test.NearlyEqual(t, math.NaN(), math.NaN(), test.NaNEqual(true)) // Passes
+func NaNEqual(equal bool) Option {
+	f := func(cfg *config) error {
+		cfg.nanEqual = equal
+
+		return nil
+	}
+
+	return option(f)
+}
+
+// validateTolerance returns an error if tolerance can't be used as a float tolerance.
+func validateTolerance(tolerance float64) error {
+	switch {
+	case math.IsNaN(tolerance):
+		return errors.New("cannot be NaN")
+	case math.IsInf(tolerance, 0):
+		return errors.New("cannot be ±infinity")
+	case tolerance < 0:
+		return fmt.Errorf("cannot be negative: %v", tolerance)
+	default:
+		return nil
+	}
+}
+
 // Title is an [Option] that sets the title of the test in the test failure log.
 //
 // The title is shown as an underlined header in the test failure, below which the
@@ -140,6 +360,9 @@ func Title(title string) Option {
 //
 // It is not necessary to include a newline character at the end of format.
 //
+// Context may be passed more than once, each context is shown on its own line in the
+// order given.
+//
 // Setting context explicitly to the empty string "" is an error and will fail the test.
 //
 // For example:
@@ -153,7 +376,322 @@ func Context(format string, args ...any) Option {
 			return errors.New("cannot set context to an empty string")
 		}
 
-		cfg.context = context
+		cfg.context = append(cfg.context, func() string { return context })
+
+		return nil
+	}
+
+	return option(f)
+}
+
+// ContextFunc is like [Context] but the context is computed by calling fn, which only
+// happens if the test fails. This avoids the cost of formatting the context on every
+// assertion in hot loops such as fuzz tests, or when the context is expensive to compute
+// e.g. dumping the state of a large structure.
+//
+// Passing a nil fn is an error and will fail the test. Like [Context], fn returning the
+// empty string is also an error, but as this is only known once the test has failed it
+// is reported in place of the context.
+//
+// For example:
+//
+//	test.Equal(t, got, want, test.ContextFunc(func() string { return cache.Dump() }))
+func ContextFunc(fn func() string) Option {
+	f := func(cfg *config) error {
+		if fn == nil {
+			return errors.New("cannot set context to a nil function")
+		}
+
+		cfg.context = append(cfg.context, fn)
+
+		return nil
+	}
+
+	return option(f)
+}
+
+// With is an [Option] that adds a key value pair to the context shown when the test fails.
+// All the pairs are shown together as an aligned table in the order they were first given,
+// passing a key that has already been set replaces its value.
+//
+// This is particularly useful in table driven tests where it is easier to read than a
+// formatted sentence:
+//
+//	test.Equal(t, got, tt.want, test.With("case", i), test.With("input", tt.input), test.With("seed", seed))
+//
+// Will show:
+//
+//	case:  3
+//	input: "foo"
+//	seed:  42
+//
+// Setting key to the empty string "" is an error and will fail the test.
+func With(key string, value any) Option {
+	f := func(cfg *config) error {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return errors.New("cannot set a context key to an empty string")
+		}
+
+		for i := range cfg.fields {
+			if cfg.fields[i].key == key {
+				cfg.fields[i].value = value
+
+				return nil
+			}
+		}
+
+		cfg.fields = append(cfg.fields, field{key: key, value: value})
+
+		return nil
+	}
+
+	return option(f)
+}
+
+// GoSyntax is an [Option] that sets whether values in the failure are shown as Go syntax,
+// formatted with the %#v verb of [fmt], rather than the default type aware formatting.
+//
+// This is useful when the default formatting hides a difference, such as the type of a
+// value or an unexported field of a type that formats itself:
+//
+//	test.Equal(t, got, want, test.GoSyntax(true))
+func GoSyntax(enabled bool) Option {
+	f := func(cfg *config) error {
+		cfg.goSyntax = enabled
+
+		return nil
+	}
+
+	return option(f)
+}
+
+// FormatWith is an [Option] that formats values of type T in the failure with fn, in place of
+// the default formatting. It is useful for types whose default formatting is noisy or unclear,
+// such as generated code or types with lots of internal state:
+//
+//	test.Equal(t, got, want, test.FormatWith(func(m Money) string {
+//		return m.Currency + " " + m.Amount.String()
+//	}))
+//
+// If T is an interface type, fn formats any value implementing it. Values of types without a
+// formatter fall back to [fmt.Formatter], [fmt.Stringer] and [fmt.GoStringer] in that order
+// before %+v, see [GoSyntax] to use %#v instead.
+//
+// To format a type the same way in every test, pass FormatWith to [SetDefaults] or [Defaults].
+// If more than one formatter applies to a value, the one passed last is used, so a formatter
+// passed to an assertion always wins over the defaults.
+//
+// Passing a nil fn is an error and will fail the test.
+func FormatWith[T any](fn func(T) string) Option {
+	f := func(cfg *config) error {
+		if fn == nil {
+			return errors.New("cannot format values with a nil function")
+		}
+
+		formatter := typeFormatter{
+			typ: reflect.TypeFor[T](),
+			format: func(v any) string {
+				value, ok := v.(T)
+				if !ok {
+					return fmt.Sprintf("%+v", v)
+				}
+
+				return fn(value)
+			},
+		}
+
+		cfg.formatters = append(cfg.formatters, formatter)
+
+		return nil
+	}
+
+	return option(f)
+}
+
+// Hint is an [Option] that adds a suggestion for how to fix the failure, shown after
+// the reason the test failed. It is intended for failures with a well known fix:
+//
+//	test.Golden(t, got, path, test.Hint("run go generate ./... to regenerate the fixtures"))
+//
+// The signature of hint allows the use of fmt print verbs to format the message in the same
+// way one might use [fmt.Sprintf]. Hint may be passed more than once, each is shown on its own
+// line. Some assertions add their own hints, which are shown after any passed by the caller.
+//
+// Setting hint explicitly to the empty string "" is an error and will fail the test.
+func Hint(format string, args ...any) Option {
+	f := func(cfg *config) error {
+		hint := strings.TrimSpace(fmt.Sprintf(format, args...))
+		if hint == "" {
+			return errors.New("cannot set hint to an empty string")
+		}
+
+		cfg.hints = append(cfg.hints, hint)
+
+		return nil
+	}
+
+	return option(f)
+}
+
+// DiffContextLines is an [Option] that sets the number of unchanged lines shown
+// around each change in a diff. This setting is only used in the diff based assertions:
+// [Diff], [DiffBytes], [DiffReader], [DiffLines], [DiffFS] and [Golden], and in the diff
+// shown when [Equal] or [EqualFunc] fail on multi-line strings.
+//
+// Setting lines to a negative number is an error and will fail the test.
+//
+// The default is 3, pass 0 to show only the lines that changed.
+//
+//	test.Diff(t, got, want, test.DiffContextLines(1))
+func DiffContextLines(lines int) Option {
+	f := func(cfg *config) error {
+		if lines < 0 {
+			return fmt.Errorf("cannot set diff context lines to a negative number: %d", lines)
+		}
+
+		cfg.diffContextLines = lines
+
+		return nil
+	}
+
+	return option(f)
+}
+
+// MaxDiffHunks is an [Option] that limits the number of hunks shown when a diff
+// fails, any further hunks are omitted and summarised in a single line. This setting
+// is only used in the diff based assertions: [Diff], [DiffBytes], [DiffReader], [DiffLines],
+// [DiffFS] and [Golden], and in the diff shown when [Equal] or [EqualFunc] fail on multi-line
+// strings. In [DiffFS] the limit applies to the diffs of all files together,
+// the diffs of any files beyond it are omitted.
+//
+// Setting hunks to less than 1 is an error and will fail the test.
+//
+// By default all hunks are shown.
+//
+//	test.Diff(t, got, want, test.MaxDiffHunks(5))
+func MaxDiffHunks(hunks int) Option {
+	f := func(cfg *config) error {
+		if hunks < 1 {
+			return fmt.Errorf("cannot set max diff hunks to less than 1: %d", hunks)
+		}
+
+		cfg.maxDiffHunks = hunks
+
+		return nil
+	}
+
+	return option(f)
+}
+
+// MaxDiffLines is an [Option] that limits the number of lines of diff shown when a
+// diff fails, any further lines are omitted and summarised in a single line. This setting
+// is only used in the diff based assertions: [Diff], [DiffBytes], [DiffReader], [DiffLines],
+// [DiffFS] and [Golden], and in the diff shown when [Equal] or [EqualFunc] fail on multi-line
+// strings. In [DiffFS] the limit applies to the diffs of all files together,
+// the diffs of any files beyond it are omitted.
+//
+// Setting lines to less than 1 is an error and will fail the test.
+//
+// By default all lines are shown.
+//
+//	test.Diff(t, got, want, test.MaxDiffLines(100))
+func MaxDiffLines(lines int) Option {
+	f := func(cfg *config) error {
+		if lines < 1 {
+			return fmt.Errorf("cannot set max diff lines to less than 1: %d", lines)
+		}
+
+		cfg.maxDiffLines = lines
+
+		return nil
+	}
+
+	return option(f)
+}
+
+// SaveDiff is an [Option] that writes the full, uncoloured diff to a file called name
+// inside the test's artifact directory (see [testing.T.ArtifactDir]) and shows the path
+// to that file in the failure log instead of the diff itself. This is useful when comparing
+// very large inputs where the diff would otherwise swamp the test log. This setting is only
+// used in the diff based assertions: [Diff], [DiffBytes], [DiffReader], [DiffLines], [DiffFS]
+// and [Golden], the diff shown when [Equal] or [EqualFunc] fail on multi-line strings is always
+// part of the failure log.
+//
+// Run the tests with -artifacts to keep the file around after the test completes,
+// otherwise it is written to a temporary directory that is removed afterwards.
+//
+// Setting name to the empty string "", or to a path that is not local to the artifact
+// directory (see [filepath.IsLocal]) is an error and will fail the test.
+//
+//	test.Diff(t, got, want, test.SaveDiff("generated.diff"))
+func SaveDiff(name string) Option {
+	f := func(cfg *config) error {
+		if name == "" {
+			return errors.New("cannot set diff file name to an empty string")
+		}
+
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("diff file name %q must be a local path", name)
+		}
+
+		cfg.saveDiff = name
+
+		return nil
+	}
+
+	return option(f)
+}
+
+// Normalize is an [Option] that applies fn to both got and want before they are compared,
+// useful for stripping out things like timestamps, absolute paths or line ending differences
+// that would otherwise cause spurious failures. This setting is only used in [Diff], [DiffBytes],
+// [DiffReader], [DiffLines], [DiffFS] and [Golden], where in the case of [DiffLines] it is applied
+// to every element and in the case of [DiffFS] to every file. Other assertions such as [Equal]
+// compare, and show, the values exactly as given.
+//
+// Normalize may be passed more than once, in which case each fn is applied in the order given.
+//
+// Passing a nil fn is an error and will fail the test.
+//
+//	crlf := func(data []byte) []byte { return bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n")) }
+//	test.Diff(t, got, want, test.Normalize(crlf))
+func Normalize(fn func(data []byte) []byte) Option {
+	f := func(cfg *config) error {
+		if fn == nil {
+			return errors.New("cannot normalize with a nil function")
+		}
+
+		cfg.normalizers = append(cfg.normalizers, fn)
+
+		return nil
+	}
+
+	return option(f)
+}
+
+// IgnorePaths is an [Option] that excludes any paths matching one of the glob patterns
+// from a comparison, using the syntax of [path.Match]. A pattern containing a "/" is matched
+// against the whole slash separated path from the root, otherwise it is matched against each
+// file or directory name at any depth. If a directory matches, everything inside it is
+// excluded too. This setting is only used in [DiffFS].
+//
+// Passing no patterns, or a malformed pattern, is an error and will fail the test.
+//
+//	test.DiffFS(t, os.DirFS(out), os.DirFS("testdata/want"), test.IgnorePaths("*.log", "tmp"))
+func IgnorePaths(patterns ...string) Option {
+	f := func(cfg *config) error {
+		if len(patterns) == 0 {
+			return errors.New("cannot ignore paths with no patterns")
+		}
+
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("invalid ignore pattern %q: %w", pattern, err)
+			}
+		}
+
+		cfg.ignorePaths = append(cfg.ignorePaths, patterns...)
 
 		return nil
 	}
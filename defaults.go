@@ -0,0 +1,140 @@
+package test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+// defaults holds the options installed by [SetDefaults] and [Defaults].
+var defaults = struct {
+	global []Option            // Applied to every assertion
+	tb     map[string][]Option // Applied to assertions in a particular test and its subtests, keyed by test name
+	mu     sync.RWMutex        // Protects both the above
+}{
+	tb: make(map[string][]Option),
+}
+
+// SetDefaults installs options applied to every assertion in the package's tests before
+// the options passed to the assertion itself, so per-call options always win. It replaces
+// any previously installed package level defaults and is intended to be called once from
+// TestMain:
+//
+//	func TestMain(m *testing.M) {
+//		if err := test.SetDefaults(test.FloatEqualityThreshold(1e-6)); err != nil {
+//			log.Fatalln(err)
+//		}
+//
+//		os.Exit(m.Run())
+//	}
+//
+// The options are validated up front and an error returned if any of them are invalid, in which
+// case the existing defaults are left in place. The [Title] of each assertion cannot be set
+// by default.
+//
+// SetDefaults may be called safely from concurrently executing goroutines.
+func SetDefaults(options ...Option) error {
+	if err := validateDefaults(options); err != nil {
+		return err
+	}
+
+	defaults.mu.Lock()
+	defer defaults.mu.Unlock()
+
+	defaults.global = options
+
+	return nil
+}
+
+// Defaults installs options applied to every subsequent assertion in the test or benchmark tb
+// belongs to, including its subtests, after any package level defaults from [SetDefaults] but
+// before the options passed to the assertion itself, so per-call options always win. Calling
+// Defaults again in the same test adds to its defaults, and a subtest's own defaults are applied
+// after those it inherits from its parents.
+//
+// The defaults are removed when tb finishes:
+//
+//	func TestSomething(t *testing.T) {
+//		test.Defaults(t, test.Context("parsing %s", file))
+//
+//		test.Equal(t, got.Name, "hello") // Failure shows the context
+//
+//		t.Run("sub", func(t *testing.T) {
+//			test.Equal(t, got.Age, 42) // So does this one
+//		})
+//	}
+//
+// Tests are identified by [testing.TB.Name], so any tb wrapping the test's own, such as a
+// custom type embedding it, shares its defaults.
+//
+// If any of the options are invalid, the test fails and no defaults are installed.
+func Defaults(tb testing.TB, options ...Option) {
+	tb.Helper()
+
+	if err := validateDefaults(options); err != nil {
+		tb.Fatalf("Defaults: %v", err)
+
+		return
+	}
+
+	name := tb.Name()
+
+	defaults.mu.Lock()
+	defer defaults.mu.Unlock()
+
+	if _, exists := defaults.tb[name]; !exists {
+		tb.Cleanup(func() {
+			defaults.mu.Lock()
+			defer defaults.mu.Unlock()
+
+			delete(defaults.tb, name)
+		})
+	}
+
+	defaults.tb[name] = append(defaults.tb[name], options...)
+}
+
+// applyDefaults applies the package level defaults then any defaults for the test tb
+// belongs to and its parents to cfg, outermost first. The options were validated when
+// installed so cannot fail. A nil tb applies only the package level defaults.
+func applyDefaults(tb testing.TB, cfg *config) {
+	defaults.mu.RLock()
+	defer defaults.mu.RUnlock()
+
+	applyOptions(defaults.global, cfg)
+
+	if tb == nil || len(defaults.tb) == 0 {
+		return
+	}
+
+	// Subtest names are their parent's name followed by a "/" and their own, so
+	// apply the defaults of each ancestor in turn starting from the top level test
+	name := tb.Name()
+	for i := range len(name) {
+		if name[i] == '/' {
+			applyOptions(defaults.tb[name[:i]], cfg)
+		}
+	}
+
+	applyOptions(defaults.tb[name], cfg)
+}
+
+// applyOptions applies options that have already been validated to cfg.
+func applyOptions(options []Option, cfg *config) {
+	for _, option := range options {
+		_ = option.apply(cfg) //nolint:errcheck // Validated when installed
+	}
+}
+
+// validateDefaults checks that options can be applied, returning the first error encountered.
+func validateDefaults(options []Option) error {
+	cfg := defaultConfig(nil)
+
+	for _, option := range options {
+		if err := option.apply(&cfg); err != nil {
+			return fmt.Errorf("could not apply options: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package test
+
+import (
+	"fmt"
+	"strings"
+)
+
+// errorChain renders err and everything it wraps as a tree, one error per line showing its
+// concrete type and message. Errors wrapping more than one error, such as those returned
+// by [errors.Join], branch into each of them.
+//
+//	*fmt.wrapError: "load config: open config.toml: no such file or directory"
+//	└── *fs.PathError: "open config.toml: no such file or directory"
+//	    └── syscall.Errno: "no such file or directory"
+func errorChain(err error) string {
+	s := &strings.Builder{}
+	writeErrorNode(s, err, "", "")
+
+	return s.String()
+}
+
+// writeErrorNode writes err to s prefixed by prefix, followed by each of the errors it wraps
+// indented by indent and connected to it by tree branches.
+func writeErrorNode(s *strings.Builder, err error, prefix, indent string) {
+	fmt.Fprintf(s, "%s%T: %q\n", prefix, err, err.Error())
+
+	children := unwrap(err)
+	for i, child := range children {
+		if i == len(children)-1 {
+			writeErrorNode(s, child, indent+"└── ", indent+"    ")
+		} else {
+			writeErrorNode(s, child, indent+"├── ", indent+"│   ")
+		}
+	}
+}
+
+// unwrap returns the errors directly wrapped by err, which may be none, one, or
+// many in the case of an Unwrap() []error method.
+func unwrap(err error) []error {
+	switch wrapper := err.(type) {
+	case interface{ Unwrap() []error }:
+		var wrapped []error
+
+		for _, child := range wrapper.Unwrap() {
+			if child != nil {
+				wrapped = append(wrapped, child)
+			}
+		}
+
+		return wrapped
+	case interface{ Unwrap() error }:
+		if child := wrapper.Unwrap(); child != nil {
+			return []error{child}
+		}
+	}
+
+	return nil
+}
+
+// errorChainSection returns a failure section showing the chain of err, or
+// nil if err is nil or wraps nothing, as then there is no chain to show.
+func errorChainSection(err error) []Section {
+	if err == nil || len(unwrap(err)) == 0 {
+		return nil
+	}
+
+	return []Section{{Label: "Error chain", Body: errorChain(err)}}
+}
@@ -0,0 +1,68 @@
+package test
+
+import "testing"
+
+// defaultFailureTitle is the title of a [Failure] that doesn't set one.
+const defaultFailureTitle = "Failed"
+
+// Failure describes a test failure raised by a custom assertion with [Fail].
+//
+// If neither Got nor Want is set, the failure isn't a comparison and both are left out.
+type Failure struct {
+	Got      any       // The actual value, shown as "Got:"
+	Want     any       // The expected value, shown as "Wanted:"
+	Title    string    // Title of the failure, may be overridden by the [Title] option, defaults to "Failed"
+	Reason   string    // Concise reason why the assertion failed, shown as "Because:"
+	Sections []Section // Any additional detail, shown after got and want in order
+}
+
+// Fail fails the test with f, rendered in exactly the same way as the built-in
+// assertions, after applying any options.
+//
+// It allows domain specific assertions to look and behave like the rest of this package,
+// accepting the same options such as [Title] and [Context]:
+//
+//	func assertValidOrder(tb testing.TB, order Order, options ...test.Option) {
+//		tb.Helper()
+//
+//		if order.Total != order.Sum() {
+//			test.Fail(tb, test.Failure{
+//				Title:  "Invalid Order",
+//				Got:    order.Total,
+//				Want:   order.Sum(),
+//				Reason: "Order total does not equal the sum of its items",
+//				Sections: []test.Section{
+//					{Label: "Items", Body: order.Items.String()},
+//				},
+//			}, options...)
+//		}
+//	}
+func Fail(tb testing.TB, f Failure, options ...Option) {
+	tb.Helper()
+
+	cfg := defaultConfig(tb)
+	cfg.title = f.Title
+
+	if cfg.title == "" {
+		cfg.title = defaultFailureTitle
+	}
+
+	for _, option := range options {
+		if err := option.apply(&cfg); err != nil {
+			tb.Fatalf("Fail: could not apply options: %v", err)
+
+			return
+		}
+	}
+
+	cfg.reason = f.Reason
+
+	fail := failure[any]{
+		got:      f.Got,
+		want:     f.Want,
+		cfg:      cfg,
+		sections: f.Sections,
+		noValues: f.Got == nil && f.Want == nil,
+	}
+	tb.Fatal(fail.String())
+}
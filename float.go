@@ -0,0 +1,476 @@
+package test
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"text/tabwriter"
+)
+
+// floatTolerance is the criterion used to decide whether two floats are nearly equal.
+type floatTolerance int
+
+const (
+	toleranceAbsolute floatTolerance = iota // |a - b| <= threshold, the default
+	toleranceRelative                       // |a - b| <= tolerance * max(|a|, |b|)
+	toleranceULP                            // a and b are at most n representable floats apart
+	toleranceAbsOrRel                       // Either of absolute or relative, like Python's math.isclose
+)
+
+// compareFloats reports whether got and want should be considered equal under the float
+// tolerance configured in cfg, along with the reason why. The reason is phrased to explain
+// a failure of [NearlyEqual] if they are not equal, and of [NotNearlyEqual] if they are.
+//
+// NaN is never equal to anything unless [NaNEqual] is set, in which case it is equal only
+// to NaN, and an infinity is only equal to an infinity of the same sign, whatever the tolerance.
+func compareFloats[T ~float32 | ~float64](gotT, wantT T, cfg config) (equal bool, reason string) {
+	// Do the maths in float64 but show the caller's values as they gave them, a float32
+	// converted to float64 would print with spurious digits
+	got, want := float64(gotT), float64(wantT)
+	bits32 := reflect.TypeFor[T]().Kind() == reflect.Float32
+
+	if equal, reason, special := compareSpecialFloats(gotT, wantT, cfg); special {
+		return equal, reason
+	}
+
+	delta := math.Abs(got - want)
+
+	switch cfg.floatTolerance {
+	case toleranceRelative:
+		relative := relativeDifference(got, want)
+		if relative <= cfg.relativeTolerance {
+			return true, fmt.Sprintf(
+				"Relative difference |%v - %v| / %v = %v is within relative tolerance of %v",
+				gotT,
+				wantT,
+				math.Max(math.Abs(got), math.Abs(want)),
+				relative,
+				cfg.relativeTolerance,
+			)
+		}
+
+		return false, fmt.Sprintf(
+			"Relative difference |%v - %v| / %v = %v exceeds relative tolerance of %v",
+			gotT,
+			wantT,
+			math.Max(math.Abs(got), math.Abs(want)),
+			relative,
+			cfg.relativeTolerance,
+		)
+	case toleranceULP:
+		ulps := ulpDistance(got, want, bits32)
+		if ulps <= uint64(cfg.ulpTolerance) {
+			return true, fmt.Sprintf(
+				"%v and %v are %s apart, within tolerance of %s",
+				gotT,
+				wantT,
+				plural(ulps, "ULP"),
+				plural(cfg.ulpTolerance, "ULP"),
+			)
+		}
+
+		return false, fmt.Sprintf(
+			"%v and %v are %s apart, exceeding tolerance of %s",
+			gotT,
+			wantT,
+			plural(ulps, "ULP"),
+			plural(cfg.ulpTolerance, "ULP"),
+		)
+	case toleranceAbsOrRel:
+		relative := relativeDifference(got, want)
+		if delta <= cfg.floatEqualityThreshold {
+			return true, fmt.Sprintf(
+				"Difference %v - %v = %v is within absolute tolerance of %v",
+				gotT,
+				wantT,
+				delta,
+				cfg.floatEqualityThreshold,
+			)
+		}
+
+		if relative <= cfg.relativeTolerance {
+			return true, fmt.Sprintf(
+				"Relative difference %v is within relative tolerance of %v",
+				relative,
+				cfg.relativeTolerance,
+			)
+		}
+
+		return false, fmt.Sprintf(
+			"Difference %v - %v = %v exceeds absolute tolerance of %v and relative difference %v exceeds relative tolerance of %v",
+			gotT,
+			wantT,
+			delta,
+			cfg.floatEqualityThreshold,
+			relative,
+			cfg.relativeTolerance,
+		)
+	default:
+		if delta <= cfg.floatEqualityThreshold {
+			return true, fmt.Sprintf(
+				"Difference %v - %v = %v is within tolerance of %v",
+				gotT,
+				wantT,
+				delta,
+				cfg.floatEqualityThreshold,
+			)
+		}
+
+		return false, fmt.Sprintf(
+			"Difference %v - %v = %v exceeds maximum tolerance of %v",
+			gotT,
+			wantT,
+			delta,
+			cfg.floatEqualityThreshold,
+		)
+	}
+}
+
+// compareSpecialFloats is the part of [compareFloats] that deals with NaN and ±Inf, for
+// which no tolerance makes sense. If neither got nor want is special, special is false and
+// the caller should compare them using the configured tolerance.
+func compareSpecialFloats[T ~float32 | ~float64](gotT, wantT T, cfg config) (equal bool, reason string, special bool) {
+	got, want := float64(gotT), float64(wantT)
+	gotNaN, wantNaN := math.IsNaN(got), math.IsNaN(want)
+
+	switch {
+	case gotNaN && wantNaN && cfg.nanEqual:
+		return true, "Both values are NaN, which are considered equal because of the NaNEqual option", true
+	case gotNaN && wantNaN:
+		return false, "Both values are NaN, which is not equal to anything, not even NaN, unless the NaNEqual option is used", true
+	case gotNaN:
+		return false, fmt.Sprintf("Got NaN, which is not equal to any number, including %v", wantT), true
+	case wantNaN:
+		return false, fmt.Sprintf("Wanted NaN, which is not equal to any number, including %v", gotT), true
+	}
+
+	gotInf, wantInf := math.IsInf(got, 0), math.IsInf(want, 0)
+
+	switch {
+	case gotInf && wantInf && got == want:
+		return true, fmt.Sprintf("Both values are %v, infinities of the same sign are equal", gotT), true
+	case gotInf && wantInf:
+		return false, fmt.Sprintf("%v and %v are infinities of opposite sign", gotT, wantT), true
+	case gotInf:
+		return false, fmt.Sprintf("Got %v, which is infinitely far from %v whatever the tolerance", gotT, wantT), true
+	case wantInf:
+		return false, fmt.Sprintf("Wanted %v, which is infinitely far from %v whatever the tolerance", wantT, gotT), true
+	}
+
+	return false, "", false
+}
+
+// relativeDifference returns |a - b| / max(|a|, |b|), or 0 if a and b are
+// both zero. Both a and b must be finite.
+func relativeDifference(a, b float64) float64 {
+	if a == b {
+		return 0
+	}
+
+	return math.Abs(a-b) / math.Max(math.Abs(a), math.Abs(b))
+}
+
+// ulpDistance returns the number of representable floats between a and b, counted
+// at float32 precision if bits32 is true. Both a and b must be finite, and ±0 are
+// considered to be the same number.
+func ulpDistance(a, b float64, bits32 bool) uint64 {
+	if a == b {
+		return 0
+	}
+
+	var ordA, ordB uint64
+	if bits32 {
+		ordA, ordB = uint64(orderedBits32(float32(a))), uint64(orderedBits32(float32(b)))
+	} else {
+		ordA, ordB = orderedBits64(a), orderedBits64(b)
+	}
+
+	return max(ordA, ordB) - min(ordA, ordB)
+}
+
+// orderedBits64 maps f onto an unsigned integer such that adjacent floats map to
+// adjacent integers, and ordering is preserved. Negative numbers count down from
+// 1<<63 and positive numbers up from it, so ±0 both map to 1<<63.
+func orderedBits64(f float64) uint64 {
+	const sign = 1 << 63
+
+	bits := math.Float64bits(f)
+	if bits&sign != 0 {
+		return sign - (bits &^ sign)
+	}
+
+	return sign + bits
+}
+
+// orderedBits32 is [orderedBits64] for float32.
+func orderedBits32(f float32) uint32 {
+	const sign = 1 << 31
+
+	bits := math.Float32bits(f)
+	if bits&sign != 0 {
+		return sign - (bits &^ sign)
+	}
+
+	return sign + bits
+}
+
+// NearlyEqualSlice is like [NearlyEqual] but compares two slices of floating point numbers
+// element by element, using the same tolerance options.
+//
+// Rather than stopping at the first difference, every element that is not nearly equal is
+// reported along with its index and the difference between got and want, and the failure
+// summarises the largest and mean absolute error across all elements.
+//
+// Slices of different lengths are never nearly equal.
+//
+//	test.NearlyEqualSlice(t, []float64{1.0, 2.0000000001}, []float64{1.0, 2.0}) // Passes
+//	test.NearlyEqualSlice(t, []float64{1.0, 2.1}, []float64{1.0, 2.0}) // Fails
+func NearlyEqualSlice[T ~float32 | ~float64](tb testing.TB, got, want []T, options ...Option) {
+	tb.Helper()
+
+	cfg := defaultConfig(tb)
+	cfg.title = "Not NearlyEqual"
+
+	for _, option := range options {
+		if err := option.apply(&cfg); err != nil {
+			tb.Fatalf("NearlyEqualSlice: could not apply options: %v", err)
+
+			return
+		}
+	}
+
+	if len(got) != len(want) {
+		cfg.reason = fmt.Sprintf("got has %s but want has %d", plural(len(got), "element"), len(want))
+		fail := failure[[]T]{
+			got:  got,
+			want: want,
+			cfg:  cfg,
+		}
+		tb.Fatal(fail.String())
+
+		return
+	}
+
+	errs := &elementErrors{}
+	for i := range got {
+		compareElement(errs, fmt.Sprintf("[%d]", i), got[i], want[i], cfg)
+	}
+
+	if len(errs.mismatches) != 0 {
+		tb.Fatal(errs.failure(got, want, cfg))
+	}
+}
+
+// NearlyEqualMatrix is like [NearlyEqualSlice] but for two dimensional slices, comparing
+// every element of every row and reporting each element that is not nearly equal by its
+// row and column index.
+//
+// Matrices with a different number of rows, or rows of different lengths, are never nearly equal.
+//
+//	got := [][]float64{{1, 0}, {0, 1}}
+//	test.NearlyEqualMatrix(t, got, identity)
+func NearlyEqualMatrix[T ~float32 | ~float64](tb testing.TB, got, want [][]T, options ...Option) {
+	tb.Helper()
+
+	cfg := defaultConfig(tb)
+	cfg.title = "Not NearlyEqual"
+
+	for _, option := range options {
+		if err := option.apply(&cfg); err != nil {
+			tb.Fatalf("NearlyEqualMatrix: could not apply options: %v", err)
+
+			return
+		}
+	}
+
+	shapeReason := ""
+	if len(got) != len(want) {
+		shapeReason = fmt.Sprintf("got has %s but want has %d", plural(len(got), "row"), len(want))
+	} else {
+		for i := range got {
+			if len(got[i]) != len(want[i]) {
+				shapeReason = fmt.Sprintf("row %d of got has %s but want has %d", i, plural(len(got[i]), "element"), len(want[i]))
+
+				break
+			}
+		}
+	}
+
+	if shapeReason != "" {
+		cfg.reason = shapeReason
+		fail := failure[[][]T]{
+			got:  got,
+			want: want,
+			cfg:  cfg,
+		}
+		tb.Fatal(fail.String())
+
+		return
+	}
+
+	errs := &elementErrors{}
+	for i := range got {
+		for j := range got[i] {
+			compareElement(errs, fmt.Sprintf("[%d][%d]", i, j), got[i][j], want[i][j], cfg)
+		}
+	}
+
+	if len(errs.mismatches) != 0 {
+		tb.Fatal(errs.failure(got, want, cfg))
+	}
+}
+
+// NearlyEqualComplex is like [NearlyEqual] but for complex numbers, the real and imaginary
+// parts are compared separately using the same tolerance options and both must be nearly
+// equal for got and want to be nearly equal.
+//
+//	test.NearlyEqualComplex(t, cmplx.Exp(complex(0, math.Pi)), -1) // Passes, thanks Euler
+func NearlyEqualComplex[T ~complex64 | ~complex128](tb testing.TB, got, want T, options ...Option) {
+	tb.Helper()
+
+	cfg := defaultConfig(tb)
+	cfg.title = "Not NearlyEqual"
+
+	for _, option := range options {
+		if err := option.apply(&cfg); err != nil {
+			tb.Fatalf("NearlyEqualComplex: could not apply options: %v", err)
+
+			return
+		}
+	}
+
+	// The real and imag builtins don't accept type parameters, so go via complex128
+	// and back down to float32 for complex64 so ULPs are counted at the right precision
+	g, w := complex128(got), complex128(want)
+
+	var realEqual, imagEqual bool
+
+	var realReason, imagReason string
+
+	if reflect.TypeFor[T]().Kind() == reflect.Complex64 {
+		realEqual, realReason = compareFloats(float32(real(g)), float32(real(w)), cfg)
+		imagEqual, imagReason = compareFloats(float32(imag(g)), float32(imag(w)), cfg)
+	} else {
+		realEqual, realReason = compareFloats(real(g), real(w), cfg)
+		imagEqual, imagReason = compareFloats(imag(g), imag(w), cfg)
+	}
+
+	if realEqual && imagEqual {
+		return
+	}
+
+	var reasons []string
+	if !realEqual {
+		reasons = append(reasons, "Real part: "+realReason)
+	}
+
+	if !imagEqual {
+		reasons = append(reasons, "Imaginary part: "+imagReason)
+	}
+
+	cfg.reason = strings.Join(reasons, "\n         ") // Line up under "Because: "
+	fail := failure[T]{
+		got:  got,
+		want: want,
+		cfg:  cfg,
+	}
+	tb.Fatal(fail.String())
+}
+
+// elementErrors accumulates the results of comparing floats element by element.
+type elementErrors struct {
+	mismatches []elementMismatch // Every element that was not nearly equal, in order
+	maxIndex   string            // Index of the element with the largest error
+	maxError   float64           // Largest absolute error of any element
+	sumError   float64           // Sum of the absolute error of every finite element
+	finite     int               // Number of elements with a finite absolute error
+	total      int               // Number of elements compared
+	bits32     bool              // Whether the elements are float32, so errors are shown at that precision
+}
+
+// elementMismatch is a single element that was not nearly equal.
+type elementMismatch struct {
+	index string // Index of the element e.g. "[2]"
+	got   string // The got element, formatted
+	want  string // The want element, formatted
+	delta string // Absolute difference between got and want, formatted at the precision of the elements
+}
+
+// compareElement compares the got and want elements at index, recording the result in errs.
+func compareElement[T ~float32 | ~float64](errs *elementErrors, index string, got, want T, cfg config) {
+	errs.total++
+	errs.bits32 = reflect.TypeFor[T]().Kind() == reflect.Float32
+	delta := math.Abs(float64(got) - float64(want))
+
+	// NaN and ±Inf would swamp the summary, they're always shown as mismatches (unless
+	// they're equal) which is enough to draw attention to them
+	if !math.IsNaN(delta) && !math.IsInf(delta, 0) {
+		errs.finite++
+		errs.sumError += delta
+
+		if delta > errs.maxError {
+			errs.maxError = delta
+			errs.maxIndex = index
+		}
+	}
+
+	if equal, _ := compareFloats(got, want, cfg); !equal {
+		errs.mismatches = append(errs.mismatches, elementMismatch{
+			index: index,
+			got:   cfg.format(got),
+			want:  cfg.format(want),
+			delta: fmt.Sprint(T(delta)),
+		})
+	}
+}
+
+// failure renders the failure log for an element wise comparison of got and want, listing
+// every mismatched element in an aligned table with a summary of the errors as the reason.
+func (e *elementErrors) failure(got, want any, cfg config) string {
+	cfg.reason = fmt.Sprintf(
+		"%d of %s are not nearly equal",
+		len(e.mismatches),
+		plural(e.total, "element"),
+	)
+
+	if e.finite != 0 {
+		cfg.reason += ", max error " + e.formatError(e.maxError)
+		if e.maxIndex != "" {
+			cfg.reason += " at " + e.maxIndex
+		}
+
+		cfg.reason += ", mean error " + e.formatError(e.sumError/float64(e.finite))
+	}
+
+	s := &strings.Builder{}
+	cfg.writeHeader(s)
+
+	gotText, wantText := cfg.formatPair(got, want)
+	writeValues(s, gotText, wantText)
+	s.WriteByte('\n')
+
+	tw := tabwriter.NewWriter(s, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "Index\tGot\tWanted\tDelta")
+
+	for _, mismatch := range e.mismatches {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", mismatch.index, mismatch.got, mismatch.want, mismatch.delta)
+	}
+
+	tw.Flush()
+
+	cfg.writeFooter(s)
+
+	return s.String()
+}
+
+// formatError formats an error computed in float64 at the precision of the elements, a
+// float32 error shown at float64 precision would print with spurious digits.
+func (e *elementErrors) formatError(f float64) string {
+	if e.bits32 {
+		return fmt.Sprint(float32(f))
+	}
+
+	return fmt.Sprint(f)
+}
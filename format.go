@@ -0,0 +1,357 @@
+package test
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// prettyWidth is the length beyond which nested values are pretty printed
+	// over multiple lines rather than on one.
+	prettyWidth = 80
+
+	// prettyIndent is the indentation of each level of a pretty printed value.
+	prettyIndent = "    "
+)
+
+// verbatim is text that has already been formatted for the failure log, and is
+// shown exactly as is rather than quoted like a string.
+type verbatim string
+
+// visit identifies a pointer, map or slice being pretty printed, so that a value
+// referring back to itself is shown by address rather than expanded forever.
+type visit struct {
+	typ reflect.Type // Type of the value, so a struct and its first field are told apart
+	ptr uintptr      // Address the value points to
+}
+
+// typeFormatter is a function that formats values of a particular type, set by [FormatWith].
+type typeFormatter struct {
+	typ    reflect.Type       // The type formatted, if an interface then any type implementing it
+	format func(v any) string // Formats a value of typ
+}
+
+// format formats v for the failure log according to the config. Values of a type with a
+// formatter set by [FormatWith] are formatted by it, otherwise values are formatted with
+// %#v if [GoSyntax] is set, or by the type aware rules described in [config.formatValue].
+func (c config) format(v any) string {
+	text := c.formatLayout(v, true)
+	if c.multiLine(v, text) {
+		return c.formatLayout(v, false)
+	}
+
+	return text
+}
+
+// formatLayout formats v as [config.format] does, on a single line if compact is set
+// or pretty printed over multiple lines if not.
+func (c config) formatLayout(v any, compact bool) string {
+	if text, ok := v.(verbatim); ok {
+		return string(text)
+	}
+
+	if c.goSyntax {
+		if text, ok := c.formatCustom(v); ok {
+			return text
+		}
+
+		return fmt.Sprintf("%#v", v)
+	}
+
+	return c.formatValue(v, compact)
+}
+
+// multiLine reports whether v, whose single line form is text, is long and nested
+// enough to be easier to read pretty printed over multiple lines.
+func (c config) multiLine(v any, text string) bool {
+	return !c.goSyntax && len(text) > prettyWidth && nested(reflect.TypeOf(v))
+}
+
+// formatValue formats v using only package level defaults, for use where there is
+// no config such as in matchers.
+func formatValue(v any) string {
+	return defaultConfig(nil).format(v)
+}
+
+// formatCustom formats v with the most recently set formatter for its type, reporting
+// whether there was one. A formatter for an interface type matches any value implementing it.
+func (c config) formatCustom(v any) (string, bool) {
+	typ := reflect.TypeOf(v)
+	if typ == nil {
+		return "", false
+	}
+
+	for _, formatter := range slices.Backward(c.formatters) {
+		if formatter.typ == typ || (formatter.typ.Kind() == reflect.Interface && typ.Implements(formatter.typ)) {
+			return formatter.format(v), true
+		}
+	}
+
+	return "", false
+}
+
+// formatPair formats got and want for the failure log, adding their dynamic types if
+// they would otherwise look identical despite having different types e.g. int(1) and
+// int64(1) held in an interface.
+//
+// If either needs pretty printing over multiple lines then both are, so they can be
+// compared line by line.
+func (c config) formatPair(got, want any) (gotText, wantText string) {
+	gotText, wantText = c.formatLayout(got, true), c.formatLayout(want, true)
+
+	if c.multiLine(got, gotText) || c.multiLine(want, wantText) {
+		gotText, wantText = c.formatLayout(got, false), c.formatLayout(want, false)
+	}
+
+	if gotText == wantText && reflect.TypeOf(got) != reflect.TypeOf(want) {
+		gotText = fmt.Sprintf("%s (%T)", gotText, got)
+		wantText = fmt.Sprintf("%s (%T)", wantText, want)
+	}
+
+	return gotText, wantText
+}
+
+// formatValue formats v for the failure log, aiming to be unambiguous:
+//
+//   - Types with a formatter set by [FormatWith] are formatted by it
+//   - Times are shown without the monotonic clock reading
+//   - Byte slices are shown as []byte("..."), so they aren't confused with strings
+//   - Errors and types implementing [fmt.Formatter], [fmt.Stringer] or [fmt.GoStringer]
+//     format themselves, in that order of preference
+//   - Strings are quoted, so whitespace, empty strings and "1" vs 1 are clear
+//   - Composite values like structs and slices apply these same rules to everything
+//     inside them, over multiple lines unless compact is set
+//
+// Everything else is formatted with %+v.
+func (c config) formatValue(v any, compact bool) string {
+	if leaf, ok := c.formatLeaf(v); ok {
+		return leaf
+	}
+
+	if !composite(reflect.TypeOf(v)) {
+		return fmt.Sprintf("%+v", v)
+	}
+
+	s := &strings.Builder{}
+	c.pretty(s, reflect.ValueOf(v), "", make(map[visit]bool), compact)
+
+	return s.String()
+}
+
+// formatLeaf formats v if it is a value that formats itself or needs special treatment,
+// reporting whether it did.
+func (c config) formatLeaf(v any) (string, bool) {
+	if text, ok := c.formatCustom(v); ok {
+		return text, true
+	}
+
+	switch value := v.(type) {
+	case nil:
+		return "<nil>", true
+	case time.Time:
+		return value.Round(0).String(), true
+	case []byte:
+		if value == nil {
+			return "[]byte(nil)", true
+		}
+
+		return fmt.Sprintf("[]byte(%q)", value), true
+	case error, fmt.Formatter, fmt.Stringer:
+		// fmt recovers from panics in these methods, such as with a nil pointer receiver
+		return fmt.Sprintf("%+v", v), true
+	case fmt.GoStringer:
+		return fmt.Sprintf("%#v", v), true
+	}
+
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.String {
+		return strconv.Quote(rv.String()), true
+	}
+
+	return "", false
+}
+
+// nested reports whether values of typ are made up of other composite values, such as
+// structs or slices of slices, and so are worth pretty printing.
+func nested(typ reflect.Type) bool {
+	if typ == nil {
+		return false
+	}
+
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	switch typ.Kind() {
+	case reflect.Struct:
+		return true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return composite(typ.Elem())
+	default:
+		return false
+	}
+}
+
+// composite reports whether typ is a struct, slice, array, map or interface, or a
+// pointer to one of those.
+func composite(typ reflect.Type) bool {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	switch typ.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Array, reflect.Map, reflect.Interface:
+		return true
+	default:
+		return false
+	}
+}
+
+// pretty writes v to s over multiple lines, each level of nesting indented one more
+// level than indent. If compact is set, v is written on a single line instead.
+//
+// The pointers, maps and slices currently being written are tracked in visiting, and one
+// that is reached again from inside itself is written as its address instead.
+func (c config) pretty(s *strings.Builder, v reflect.Value, indent string, visiting map[visit]bool, compact bool) {
+	if v.CanInterface() {
+		if leaf, ok := c.formatLeaf(v.Interface()); ok {
+			s.WriteString(leaf)
+
+			return
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			break
+		}
+
+		key := visit{typ: v.Type(), ptr: v.Pointer()}
+		if visiting[key] {
+			fmt.Fprintf(s, "%#x", key.ptr)
+
+			return
+		}
+
+		visiting[key] = true
+		defer delete(visiting, key)
+	}
+
+	// In compact mode elements are separated by a space rather than each
+	// placed on its own indented line
+	inner, open, sep, end := indent+prettyIndent, "\n", ",\n", indent
+	if compact {
+		inner, open, sep, end = "", "", " ", ""
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			s.WriteString("<nil>")
+
+			return
+		}
+
+		if v.Kind() == reflect.Pointer && composite(v.Type()) {
+			s.WriteByte('&')
+		}
+
+		c.pretty(s, v.Elem(), indent, visiting, compact)
+	case reflect.Struct:
+		if v.NumField() == 0 {
+			s.WriteString("{}")
+
+			return
+		}
+
+		s.WriteString("{" + open)
+
+		for i := range v.NumField() {
+			if compact && i > 0 {
+				s.WriteString(sep)
+			}
+
+			fmt.Fprintf(s, "%s%s: ", inner, v.Type().Field(i).Name)
+			c.pretty(s, v.Field(i), inner, visiting, compact)
+
+			if !compact {
+				s.WriteString(sep)
+			}
+		}
+
+		s.WriteString(end + "}")
+	case reflect.Slice, reflect.Array:
+		if v.Len() == 0 {
+			s.WriteString("[]")
+
+			return
+		}
+
+		s.WriteString("[" + open)
+
+		for i := range v.Len() {
+			if compact && i > 0 {
+				s.WriteString(sep)
+			}
+
+			s.WriteString(inner)
+			c.pretty(s, v.Index(i), inner, visiting, compact)
+
+			if !compact {
+				s.WriteString(sep)
+			}
+		}
+
+		s.WriteString(end + "]")
+	case reflect.Map:
+		if v.Len() == 0 {
+			s.WriteString("map[]")
+
+			return
+		}
+
+		// Sort by the formatted key so the output is stable
+		keys := v.MapKeys()
+		slices.SortFunc(keys, func(a, b reflect.Value) int {
+			return strings.Compare(c.formatReflected(a), c.formatReflected(b))
+		})
+
+		s.WriteString("map[" + open)
+
+		for i, key := range keys {
+			if compact && i > 0 {
+				s.WriteString(sep)
+			}
+
+			fmt.Fprintf(s, "%s%s: ", inner, c.formatReflected(key))
+			c.pretty(s, v.MapIndex(key), inner, visiting, compact)
+
+			if !compact {
+				s.WriteString(sep)
+			}
+		}
+
+		s.WriteString(end + "]")
+	default:
+		s.WriteString(c.formatReflected(v))
+	}
+}
+
+// formatReflected formats a single value on one line, values that cannot be
+// converted back to an interface, such as unexported struct fields, are formatted
+// by fmt directly.
+func (c config) formatReflected(v reflect.Value) string {
+	if v.CanInterface() {
+		return c.formatValue(v.Interface(), true)
+	}
+
+	if v.Kind() == reflect.String {
+		return strconv.Quote(v.String())
+	}
+
+	return fmt.Sprintf("%+v", v)
+}
@@ -0,0 +1,251 @@
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// DiffFS fails if the file trees got and want differ, reporting any files missing
+// from either side, any files whose mode differs and a rich unified diff of the contents
+// of each file that differs, rendered in the same way as [DiffBytes].
+//
+// Only files are compared, so directories that are empty on one side and absent on the
+// other are not considered a difference.
+//
+// Paths can be excluded from the comparison with the [IgnorePaths] option, and the
+// [Normalize] option is applied to every file before comparing.
+//
+//	test.DiffFS(t, os.DirFS(outDir), os.DirFS("testdata/want"))
+func DiffFS(tb testing.TB, got, want fs.FS, options ...Option) {
+	tb.Helper()
+
+	cfg := defaultConfig(tb)
+	cfg.title = "DiffFS"
+
+	for _, option := range options {
+		if err := option.apply(&cfg); err != nil {
+			tb.Fatalf("DiffFS: could not apply options: %v", err)
+
+			return
+		}
+	}
+
+	gotFiles, err := walkFiles(got, cfg.ignorePaths)
+	if err != nil {
+		tb.Fatalf("DiffFS: could not read got: %v", err)
+
+		return
+	}
+
+	wantFiles, err := walkFiles(want, cfg.ignorePaths)
+	if err != nil {
+		tb.Fatalf("DiffFS: could not read want: %v", err)
+
+		return
+	}
+
+	var (
+		missing    []string // In want but not got
+		unexpected []string // In got but not want
+		modes      []string // Mode differences, already formatted
+		diffs      []byte   // Rendered content diffs of every differing file
+		differing  int      // Number of files that differ in any way
+		omitted    int      // Number of files whose diff didn't fit within the diff limits
+	)
+
+	// The diff limits apply to the diffs of all the files together, each file
+	// gets whatever is left over from those before it. A saved diff is always in full.
+	fileCfg := cfg
+
+	for _, name := range slices.Sorted(maps.Keys(wantFiles)) {
+		gotMode, ok := gotFiles[name]
+		if !ok {
+			missing = append(missing, name)
+			differing++
+
+			continue
+		}
+
+		wantMode := wantFiles[name]
+		fileDiffers := false
+
+		if gotMode != wantMode {
+			modes = append(modes, fmt.Sprintf("%s: got %v, wanted %v", name, gotMode, wantMode))
+			fileDiffers = true
+		}
+
+		gotData, err := fs.ReadFile(got, name)
+		if err != nil {
+			tb.Fatalf("DiffFS: could not read got: %v", err)
+
+			return
+		}
+
+		wantData, err := fs.ReadFile(want, name)
+		if err != nil {
+			tb.Fatalf("DiffFS: could not read want: %v", err)
+
+			return
+		}
+
+		rendered, hunks := renderDiffHunks("want/"+name, "got/"+name, gotData, wantData, fileCfg)
+		switch {
+		case rendered == nil:
+			// Contents are the same
+		case cfg.saveDiff == "" && (exhausted(cfg.maxDiffHunks, fileCfg.maxDiffHunks) || exhausted(cfg.maxDiffLines, fileCfg.maxDiffLines)):
+			omitted++
+			fileDiffers = true
+		default:
+			if len(diffs) != 0 {
+				diffs = append(diffs, '\n')
+			}
+
+			if isBinary(gotData) || isBinary(wantData) {
+				diffs = fmt.Appendf(diffs, "Binary file %s differs\n\n", name)
+			}
+
+			diffs = append(diffs, rendered...)
+			fileDiffers = true
+
+			if fileCfg.maxDiffHunks > 0 {
+				fileCfg.maxDiffHunks -= hunks
+			}
+
+			if fileCfg.maxDiffLines > 0 {
+				fileCfg.maxDiffLines -= bytes.Count(rendered, []byte("\n"))
+			}
+		}
+
+		if fileDiffers {
+			differing++
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(gotFiles)) {
+		if _, ok := wantFiles[name]; !ok {
+			unexpected = append(unexpected, name)
+			differing++
+		}
+	}
+
+	if differing == 0 {
+		return
+	}
+
+	var sections []string
+
+	sections = appendSection(sections, "Missing from got:", missing)
+	sections = appendSection(sections, "Unexpected in got:", unexpected)
+	sections = appendSection(sections, "Mode differs:", modes)
+
+	if omitted != 0 {
+		diffs = fmt.Appendf(diffs, "\n... diffs of %s omitted\n", plural(omitted, "more file"))
+	}
+
+	if len(diffs) != 0 {
+		if cfg.saveDiff != "" {
+			saved, err := saveDiff(tb, cfg, diffs)
+			if err != nil {
+				tb.Fatalf("DiffFS: could not save diff: %v", err)
+
+				return
+			}
+
+			sections = append(sections, fmt.Sprintf("Diff written to %s\n", saved))
+		} else {
+			sections = append(sections, string(diffs))
+		}
+	}
+
+	s := &strings.Builder{}
+	cfg.writeHeader(s)
+	s.WriteString(strings.Join(sections, "\n"))
+	cfg.reason = fmt.Sprintf("Found differences in %s", plural(differing, "file"))
+	cfg.writeFooter(s)
+	tb.Fatal(s.String())
+}
+
+// walkFiles returns the mode of every file in fsys, keyed by its path, skipping
+// any paths that match one of the ignore patterns.
+func walkFiles(fsys fs.FS, ignore []string) (map[string]fs.FileMode, error) {
+	files := make(map[string]fs.FileMode)
+
+	err := fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if name != "." && ignored(name, ignore) {
+			if entry.IsDir() {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
+		if entry.IsDir() {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		files[name] = info.Mode()
+
+		return nil
+	})
+
+	return files, err
+}
+
+// ignored reports whether name matches any of the patterns, patterns have
+// already been validated by [IgnorePaths]. A pattern without a "/" is matched
+// against the last element of name, so it applies at any depth.
+func ignored(name string, patterns []string) bool {
+	for _, pattern := range patterns {
+		target := name
+		if !strings.Contains(pattern, "/") {
+			target = path.Base(name)
+		}
+
+		if matched, _ := path.Match(pattern, target); matched {
+			return true
+		}
+	}
+
+	return false
+}
+
+// exhausted reports whether a diff limit has been used up, given the remaining amount
+// of it. A limit of 0 is no limit and so is never used up.
+func exhausted(limit, remaining int) bool {
+	return limit > 0 && remaining <= 0
+}
+
+// appendSection appends a section made up of a heading followed by each item
+// indented on its own line to sections, unless there are no items.
+func appendSection(sections []string, heading string, items []string) []string {
+	if len(items) == 0 {
+		return sections
+	}
+
+	s := &strings.Builder{}
+	s.WriteString(heading)
+	s.WriteByte('\n')
+
+	for _, item := range items {
+		s.WriteString("  ")
+		s.WriteString(item)
+		s.WriteByte('\n')
+	}
+
+	return append(sections, s.String())
+}
@@ -0,0 +1,126 @@
+package test
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"testing"
+)
+
+// GoldenUpdateEnv is the environment variable that, when set to a true value as
+// understood by [strconv.ParseBool], causes [Golden] to rewrite golden files rather
+// than compare against them.
+const GoldenUpdateEnv = "TEST_UPDATE_GOLDEN"
+
+// Golden fails if got does not match the contents of the golden file at path byte for byte,
+// after applying any [Normalize] functions, showing a rich unified diff of the two in the same
+// way as [DiffBytes].
+//
+// If the golden file does not exist, it is created with the contents of got (along with
+// any missing parent directories) and the test passes. The golden file is rewritten with
+// the contents of got if the test binary has a boolean -update flag that is set, or the
+// [GoldenUpdateEnv] environment variable is set to a true value. Any golden files that
+// are created or rewritten are reported in the test log.
+//
+// Golden files are conventionally kept under testdata, relative paths are resolved
+// against the package directory in which the test is running.
+//
+//	got := render(input)
+//	test.Golden(t, got, "testdata/render.golden")
+func Golden(tb testing.TB, got []byte, path string, options ...Option) {
+	tb.Helper()
+
+	cfg := defaultConfig(tb)
+	cfg.title = "Golden"
+
+	for _, option := range options {
+		if err := option.apply(&cfg); err != nil {
+			tb.Fatalf("Golden: could not apply options: %v", err)
+
+			return
+		}
+	}
+
+	want, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		if err = writeFile(path, got); err != nil {
+			tb.Fatalf("Golden: could not create golden file: %v", err)
+
+			return
+		}
+
+		tb.Logf("Golden: created %s", path)
+
+		return
+	}
+
+	if err != nil {
+		tb.Fatalf("Golden: could not read golden file: %v", err)
+
+		return
+	}
+
+	if updateGolden() {
+		if bytes.Equal(got, want) {
+			return
+		}
+
+		if err = writeFile(path, got); err != nil {
+			tb.Fatalf("Golden: could not update golden file: %v", err)
+
+			return
+		}
+
+		tb.Logf("Golden: updated %s", path)
+
+		return
+	}
+
+	// Golden files must match byte for byte, the diff adds any missing trailing newline
+	// so can't be relied upon to spot a difference
+	normalizedGot, normalizedWant := normalize(got, cfg.normalizers), normalize(want, cfg.normalizers)
+	if bytes.Equal(normalizedGot, normalizedWant) {
+		return
+	}
+
+	cfg.reason = fmt.Sprintf("got does not match golden file %s", path)
+	cfg.hints = append(cfg.hints, fmt.Sprintf("If this change is expected, re-run with %s=1 to update the golden file", GoldenUpdateEnv))
+
+	rendered := renderDiff("want", "got", got, want, cfg)
+	if rendered == nil {
+		rendered = []byte(trailingNewlineDifference(normalizedGot, normalizedWant))
+	}
+
+	reportDiff(tb, "Golden", rendered, cfg)
+}
+
+// trailingNewlineDifference describes the difference between got and want when they
+// differ only in whether they end in a newline, which doesn't show in a diff.
+func trailingNewlineDifference(got, want []byte) string {
+	if bytes.HasSuffix(got, []byte("\n")) {
+		return "got ends in a newline but the golden file does not\n"
+	}
+
+	return "the golden file ends in a newline but got does not\n"
+}
+
+// updateGolden reports whether golden files should be rewritten, either because
+// a boolean -update flag is set or the [GoldenUpdateEnv] environment variable is true.
+//
+// The flag is looked up rather than defined here, so as not to clash with the -update
+// flag many test packages (and snapshot testing libraries) already define.
+func updateGolden() bool {
+	if f := flag.Lookup("update"); f != nil {
+		if update, err := strconv.ParseBool(f.Value.String()); err == nil && update {
+			return true
+		}
+	}
+
+	update, err := strconv.ParseBool(os.Getenv(GoldenUpdateEnv))
+
+	return err == nil && update
+}
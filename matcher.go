@@ -0,0 +1,360 @@
+package test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// Matcher is a reusable, composable check on a value of type T, used with [That].
+//
+// Matchers describe what they expect and, when a value doesn't match, explain why. This
+// allows them to be combined with [Not], [AllOf], [AnyOf], [Each] and [HasField] to build
+// more complex checks that still fail with a useful explanation.
+//
+// Most matchers can be made with [MatchFunc], implement Matcher directly when you need
+// a more detailed explanation of a mismatch.
+type Matcher[T any] interface {
+	// Match reports whether got matches.
+	Match(got T) bool
+
+	// Describe describes the values the matcher matches, phrased such that
+	// "got is <description>" reads naturally e.g. "equal to 42".
+	Describe() string
+
+	// Explain explains why got does not match, it is only called if Match returned
+	// false. The explanation may span multiple lines, in which case any lines after
+	// the first are shown indented beneath it.
+	Explain(got T) string
+}
+
+// That fails if got does not match the matcher, showing the matcher's description and
+// its explanation of the mismatch, nested for any combined matchers.
+//
+//	test.That(t, count, test.AllOf(test.MatchFunc("positive", isPositive), test.Not(test.EqualTo(13))))
+//	test.That(t, users, test.Each(test.HasField("Name", User.GetName, test.Not(test.EqualTo("")))))
+func That[T any](tb testing.TB, got T, matcher Matcher[T], options ...Option) {
+	tb.Helper()
+
+	cfg := defaultConfig(tb)
+	cfg.title = "No Match"
+
+	for _, option := range options {
+		if err := option.apply(&cfg); err != nil {
+			tb.Fatalf("That: could not apply options: %v", err)
+
+			return
+		}
+	}
+
+	if matcher.Match(got) {
+		return
+	}
+
+	fail := failure[verbatim]{
+		got:      verbatim(cfg.format(got)),
+		want:     verbatim(matcher.Describe()),
+		cfg:      cfg,
+		sections: []Section{{Label: "Explanation", Body: matcher.Explain(got)}},
+	}
+	tb.Fatal(fail.String())
+}
+
+// MatchFunc returns a [Matcher] that matches any value for which match returns true.
+//
+// The description is used to describe and explain the matcher, and should be phrased
+// such that "got is <description>" and "got is not <description>" read naturally.
+//
+//	even := test.MatchFunc("even", func(n int) bool { return n%2 == 0 })
+func MatchFunc[T any](description string, match func(got T) bool) Matcher[T] {
+	return funcMatcher[T]{description: description, match: match}
+}
+
+// EqualTo returns a [Matcher] that matches values equal to want, like [Equal].
+func EqualTo[T comparable](want T) Matcher[T] {
+	return funcMatcher[T]{
+		description: "equal to " + formatValue(want),
+		match:       func(got T) bool { return got == want },
+	}
+}
+
+// NearlyEqualTo returns a [Matcher] that matches floats nearly equal to want, like [NearlyEqual].
+//
+// The float tolerance options accepted by [NearlyEqual] may be passed to configure it, if any
+// of them are invalid the matcher never matches and explains why. As a matcher is not tied to
+// a test, only package level defaults from [SetDefaults] apply and not those from [Defaults].
+func NearlyEqualTo[T ~float32 | ~float64](want T, options ...Option) Matcher[T] {
+	cfg := defaultConfig(nil)
+
+	var err error
+	for _, option := range options {
+		if err = option.apply(&cfg); err != nil {
+			break
+		}
+	}
+
+	return nearlyEqualMatcher[T]{want: want, cfg: cfg, err: err}
+}
+
+// IsError returns a [Matcher] that matches errors matching target as reported by
+// [errors.Is], like [ErrorIs].
+func IsError(target error) Matcher[error] {
+	return errorMatcher{target: target}
+}
+
+// Not returns a [Matcher] that matches any value that matcher does not.
+func Not[T any](matcher Matcher[T]) Matcher[T] {
+	return notMatcher[T]{matcher: matcher}
+}
+
+// AllOf returns a [Matcher] that matches values matching every one of matchers.
+func AllOf[T any](matchers ...Matcher[T]) Matcher[T] {
+	return allOfMatcher[T]{matchers: matchers}
+}
+
+// AnyOf returns a [Matcher] that matches values matching at least one of matchers.
+func AnyOf[T any](matchers ...Matcher[T]) Matcher[T] {
+	return anyOfMatcher[T]{matchers: matchers}
+}
+
+// Each returns a [Matcher] that matches slices where every element matches matcher,
+// an empty slice always matches.
+func Each[T any](matcher Matcher[T]) Matcher[[]T] {
+	return eachMatcher[T]{matcher: matcher}
+}
+
+// HasField returns a [Matcher] that matches values where the field returned by get matches
+// matcher. The name is used to describe the field in any failure.
+//
+//	test.That(t, user, test.HasField("Age", func(u User) int { return u.Age }, test.EqualTo(42)))
+func HasField[T, F any](name string, get func(got T) F, matcher Matcher[F]) Matcher[T] {
+	return fieldMatcher[T, F]{name: name, get: get, matcher: matcher}
+}
+
+// funcMatcher is a [Matcher] built from a description and a match function.
+type funcMatcher[T any] struct {
+	match       func(got T) bool // Reports whether got matches
+	description string           // Describes the values that match
+}
+
+func (m funcMatcher[T]) Match(got T) bool { return m.match(got) }
+
+func (m funcMatcher[T]) Describe() string { return m.description }
+
+func (m funcMatcher[T]) Explain(got T) string {
+	return fmt.Sprintf("%s is not %s", formatValue(got), m.description)
+}
+
+// nearlyEqualMatcher is the [Matcher] returned by [NearlyEqualTo].
+type nearlyEqualMatcher[T ~float32 | ~float64] struct {
+	err  error  // Any error applying the options, if set the matcher never matches
+	cfg  config // Configures the float tolerance
+	want T      // The expected value
+}
+
+func (m nearlyEqualMatcher[T]) Match(got T) bool {
+	if m.err != nil {
+		return false
+	}
+
+	equal, _ := compareFloats(got, m.want, m.cfg)
+
+	return equal
+}
+
+func (m nearlyEqualMatcher[T]) Describe() string {
+	return fmt.Sprintf("nearly equal to %v", m.want)
+}
+
+func (m nearlyEqualMatcher[T]) Explain(got T) string {
+	if m.err != nil {
+		return fmt.Sprintf("could not apply options: %v", m.err)
+	}
+
+	_, reason := compareFloats(got, m.want, m.cfg)
+
+	return reason
+}
+
+// errorMatcher is the [Matcher] returned by [IsError].
+type errorMatcher struct {
+	target error // The error to match
+}
+
+func (m errorMatcher) Match(got error) bool { return errors.Is(got, m.target) }
+
+func (m errorMatcher) Describe() string {
+	return fmt.Sprintf("an error matching %v", m.target)
+}
+
+func (m errorMatcher) Explain(got error) string {
+	if got == nil {
+		return fmt.Sprintf("<nil> is not %s", m.Describe())
+	}
+
+	return fmt.Sprintf("no error in the chain matches %v\n%s", m.target, strings.TrimSuffix(errorChain(got), "\n"))
+}
+
+// notMatcher is the [Matcher] returned by [Not].
+type notMatcher[T any] struct {
+	matcher Matcher[T] // The matcher to negate
+}
+
+func (m notMatcher[T]) Match(got T) bool { return !m.matcher.Match(got) }
+
+func (m notMatcher[T]) Describe() string { return "not " + m.matcher.Describe() }
+
+func (m notMatcher[T]) Explain(got T) string {
+	return fmt.Sprintf("%s is %s", formatValue(got), m.matcher.Describe())
+}
+
+// allOfMatcher is the [Matcher] returned by [AllOf].
+type allOfMatcher[T any] struct {
+	matchers []Matcher[T] // All of these must match
+}
+
+func (m allOfMatcher[T]) Match(got T) bool {
+	for _, matcher := range m.matchers {
+		if !matcher.Match(got) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (m allOfMatcher[T]) Describe() string { return "all of (" + describeAll(m.matchers) + ")" }
+
+func (m allOfMatcher[T]) Explain(got T) string {
+	var explanations []string
+
+	for _, matcher := range m.matchers {
+		if !matcher.Match(got) {
+			explanations = append(explanations, matcher.Explain(got))
+		}
+	}
+
+	heading := fmt.Sprintf("%d of %d matchers did not match", len(explanations), len(m.matchers))
+
+	return explanationTree(heading, explanations)
+}
+
+// anyOfMatcher is the [Matcher] returned by [AnyOf].
+type anyOfMatcher[T any] struct {
+	matchers []Matcher[T] // At least one of these must match
+}
+
+func (m anyOfMatcher[T]) Match(got T) bool {
+	for _, matcher := range m.matchers {
+		if matcher.Match(got) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (m anyOfMatcher[T]) Describe() string { return "any of (" + describeAll(m.matchers) + ")" }
+
+func (m anyOfMatcher[T]) Explain(got T) string {
+	explanations := make([]string, 0, len(m.matchers))
+	for _, matcher := range m.matchers {
+		explanations = append(explanations, matcher.Explain(got))
+	}
+
+	heading := fmt.Sprintf("none of %s matched", plural(len(m.matchers), "matcher"))
+
+	return explanationTree(heading, explanations)
+}
+
+// eachMatcher is the [Matcher] returned by [Each].
+type eachMatcher[T any] struct {
+	matcher Matcher[T] // Every element must match this
+}
+
+func (m eachMatcher[T]) Match(got []T) bool {
+	for _, element := range got {
+		if !m.matcher.Match(element) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (m eachMatcher[T]) Describe() string { return "each element " + m.matcher.Describe() }
+
+func (m eachMatcher[T]) Explain(got []T) string {
+	var explanations []string
+
+	for i, element := range got {
+		if !m.matcher.Match(element) {
+			explanations = append(explanations, fmt.Sprintf("[%d]: %s", i, m.matcher.Explain(element)))
+		}
+	}
+
+	heading := fmt.Sprintf("%d of %s did not match", len(explanations), plural(len(got), "element"))
+
+	return explanationTree(heading, explanations)
+}
+
+// fieldMatcher is the [Matcher] returned by [HasField].
+type fieldMatcher[T, F any] struct {
+	get     func(got T) F // Returns the field from got
+	matcher Matcher[F]    // The field must match this
+	name    string        // Name of the field
+}
+
+func (m fieldMatcher[T, F]) Match(got T) bool { return m.matcher.Match(m.get(got)) }
+
+func (m fieldMatcher[T, F]) Describe() string {
+	return fmt.Sprintf("field %s %s", m.name, m.matcher.Describe())
+}
+
+func (m fieldMatcher[T, F]) Explain(got T) string {
+	return explanationTree("field "+m.name, []string{m.matcher.Explain(m.get(got))})
+}
+
+// describeAll joins the descriptions of matchers into a comma separated list.
+func describeAll[T any](matchers []Matcher[T]) string {
+	descriptions := make([]string, 0, len(matchers))
+	for _, matcher := range matchers {
+		descriptions = append(descriptions, matcher.Describe())
+	}
+
+	return strings.Join(descriptions, ", ")
+}
+
+// explanationTree renders heading followed by each of the explanations as branches of
+// a tree beneath it, any lines after the first of an explanation are indented so nested
+// explanations form a tree of their own.
+//
+//	2 of 3 matchers did not match
+//	├── 5 is not even
+//	└── 5 is not greater than 10
+func explanationTree(heading string, explanations []string) string {
+	s := &strings.Builder{}
+	s.WriteString(heading)
+
+	for i, explanation := range explanations {
+		branch, indent := "├── ", "│   "
+		if i == len(explanations)-1 {
+			branch, indent = "└── ", "    "
+		}
+
+		for j, line := range strings.Split(explanation, "\n") {
+			s.WriteByte('\n')
+
+			if j == 0 {
+				s.WriteString(branch)
+			} else {
+				s.WriteString(indent)
+			}
+
+			s.WriteString(line)
+		}
+	}
+
+	return s.String()
+}
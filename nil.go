@@ -0,0 +1,250 @@
+package test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// nilness describes whether a value is nil, as far as the nil assertions are concerned.
+type nilness int
+
+const (
+	nonNil   nilness = iota // A value of a type that can be nil, that isn't
+	isNil                   // A nil pointer, map, slice, channel, func or interface
+	typedNil                // A non-nil interface holding a nil value, e.g. a nil *MyError as an error
+	neverNil                // A value of a type that can never be nil, e.g. int or a struct
+)
+
+// Nil fails if got is not nil. Unlike comparing with [Equal], Nil works for any type that can
+// be nil: pointers, maps, slices, channels, funcs and interfaces.
+//
+// Nil also fails if got is an interface holding a nil value, for example a nil *MyError returned
+// as an error. Such an interface is not nil (got != nil is true) which is a common source of bugs,
+// so the failure explains exactly what happened. Passing a value of a type that can never be nil
+// also fails.
+//
+//	var m map[string]int
+//	test.Nil(t, m) // Passes
+//	test.Nil(t, []int{1}) // Fails
+func Nil[T any](tb testing.TB, got T, options ...Option) {
+	tb.Helper()
+
+	cfg := defaultConfig(tb)
+	cfg.title = "Not Nil"
+
+	for _, option := range options {
+		if err := option.apply(&cfg); err != nil {
+			tb.Fatalf("Nil: could not apply options: %v", err)
+
+			return
+		}
+	}
+
+	switch checkNil(got) {
+	case isNil:
+		return
+	case typedNil:
+		cfg.reason = typedNilReason(got)
+	case neverNil:
+		cfg.reason = fmt.Sprintf("got is of type %s, which can never be nil", reflect.TypeFor[T]())
+	case nonNil:
+		// Nothing to add, got and want say it all
+	}
+
+	fail := failure[verbatim]{
+		got:  verbatim(describeNil(got, cfg)),
+		want: "<nil>",
+		cfg:  cfg,
+	}
+	tb.Fatal(fail.String())
+}
+
+// NotNil fails if got is nil, it is the opposite of [Nil].
+//
+// An interface holding a nil value, for example a nil *MyError returned as an error, also
+// fails. Although such an interface is not nil, the value it holds is and calling methods on it
+// will likely panic, so the failure explains what happened. A value of a type that can never be
+// nil always passes.
+//
+//	test.NotNil(t, &Thing{}) // Passes
+//	test.NotNil(t, (*Thing)(nil)) // Fails
+func NotNil[T any](tb testing.TB, got T, options ...Option) {
+	tb.Helper()
+
+	cfg := defaultConfig(tb)
+	cfg.title = "Nil"
+
+	for _, option := range options {
+		if err := option.apply(&cfg); err != nil {
+			tb.Fatalf("NotNil: could not apply options: %v", err)
+
+			return
+		}
+	}
+
+	switch checkNil(got) {
+	case nonNil, neverNil:
+		return
+	case typedNil:
+		cfg.reason = typedNilReason(got)
+	case isNil:
+		// Nothing to add, got says it all
+	}
+
+	fail := failure[verbatim]{
+		got:  verbatim(describeNil(got, cfg)),
+		want: "not nil",
+		cfg:  cfg,
+	}
+	tb.Fatal(fail.String())
+}
+
+// Zero fails if got is not the zero value for its type, e.g. 0, "", false, nil or a struct
+// whose fields are all zero.
+//
+// An interface holding a nil value is not the zero value of that interface, and the failure
+// explains this if so.
+//
+//	test.Zero(t, time.Time{}) // Passes
+//	test.Zero(t, 42) // Fails
+func Zero[T any](tb testing.TB, got T, options ...Option) {
+	tb.Helper()
+
+	cfg := defaultConfig(tb)
+	cfg.title = "Not Zero"
+
+	for _, option := range options {
+		if err := option.apply(&cfg); err != nil {
+			tb.Fatalf("Zero: could not apply options: %v", err)
+
+			return
+		}
+	}
+
+	if reflect.ValueOf(&got).Elem().IsZero() {
+		return
+	}
+
+	if checkNil(got) == typedNil {
+		cfg.reason = typedNilReason(got)
+		fail := failure[verbatim]{
+			got:  verbatim(describeNil(got, cfg)),
+			want: "<nil>",
+			cfg:  cfg,
+		}
+		tb.Fatal(fail.String())
+
+		return
+	}
+
+	var zero T
+
+	fail := failure[T]{
+		got:  got,
+		want: zero,
+		cfg:  cfg,
+	}
+	tb.Fatal(fail.String())
+}
+
+// NotZero fails if got is the zero value for its type, it is the opposite of [Zero].
+//
+//	test.NotZero(t, 42) // Passes
+//	test.NotZero(t, "") // Fails
+func NotZero[T any](tb testing.TB, got T, options ...Option) {
+	tb.Helper()
+
+	cfg := defaultConfig(tb)
+	cfg.title = "Zero"
+
+	for _, option := range options {
+		if err := option.apply(&cfg); err != nil {
+			tb.Fatalf("NotZero: could not apply options: %v", err)
+
+			return
+		}
+	}
+
+	if !reflect.ValueOf(&got).Elem().IsZero() {
+		return
+	}
+
+	cfg.reason = fmt.Sprintf("got is the zero value of %s", reflect.TypeFor[T]())
+
+	fail := failure[verbatim]{
+		got:  verbatim(cfg.format(got)),
+		want: "not zero",
+		cfg:  cfg,
+	}
+	tb.Fatal(fail.String())
+}
+
+// checkNil reports the nilness of got, the static type T is needed to tell
+// a nil interface apart from an interface holding a nil value.
+func checkNil[T any](got T) nilness {
+	typ := reflect.TypeFor[T]()
+	value := reflect.ValueOf(&got).Elem()
+
+	if typ.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return isNil
+		}
+
+		inner := value.Elem()
+		if canBeNil(inner.Kind()) && inner.IsNil() {
+			return typedNil
+		}
+
+		return nonNil
+	}
+
+	if !canBeNil(typ.Kind()) {
+		return neverNil
+	}
+
+	if value.IsNil() {
+		return isNil
+	}
+
+	return nonNil
+}
+
+// canBeNil reports whether values of kind can be nil.
+func canBeNil(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Chan, reflect.Func, reflect.Interface, reflect.UnsafePointer:
+		return true
+	default:
+		return false
+	}
+}
+
+// describeNil formats got for the failure log of the nil assertions, showing the
+// dynamic type of any nil value so a typed nil is distinguishable from a plain nil.
+func describeNil[T any](got T, cfg config) string {
+	switch checkNil(got) {
+	case isNil:
+		if reflect.TypeFor[T]().Kind() == reflect.Interface {
+			return "<nil>"
+		}
+
+		return fmt.Sprintf("(%s)(nil)", reflect.TypeFor[T]())
+	case typedNil:
+		return fmt.Sprintf("%s((%T)(nil))", reflect.TypeFor[T](), got)
+	default:
+		return cfg.format(got)
+	}
+}
+
+// typedNilReason explains the typed nil in an interface trap for got, which must
+// be an interface holding a nil value.
+func typedNilReason[T any](got T) string {
+	return fmt.Sprintf(
+		"got is a non-nil %s holding a nil %T. An interface is only nil if both its type and value are nil, "+
+			"so got != nil is true even though the value inside it is nil. This usually means a nil pointer was "+
+			"returned or assigned as an interface, return a literal nil instead",
+		reflect.TypeFor[T](),
+		got,
+	)
+}
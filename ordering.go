@@ -0,0 +1,324 @@
+package test
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+	"testing"
+	"text/tabwriter"
+)
+
+// relation is an ordering relation expected to hold between got and want.
+type relation struct {
+	symbol string           // How the relation is written e.g. "<"
+	holds  func(c int) bool // Whether the relation holds, given the result of compare(got, want)
+}
+
+// sortedWindow is the number of elements shown either side of the first out
+// of order pair when a sorted assertion fails.
+const sortedWindow = 3
+
+var (
+	less           = relation{symbol: "<", holds: func(c int) bool { return c < 0 }}
+	lessOrEqual    = relation{symbol: "<=", holds: func(c int) bool { return c <= 0 }}
+	greater        = relation{symbol: ">", holds: func(c int) bool { return c > 0 }}
+	greaterOrEqual = relation{symbol: ">=", holds: func(c int) bool { return c >= 0 }}
+)
+
+// Less fails if got is not strictly less than want.
+//
+//	test.Less(t, latency, 100*time.Millisecond) // Passes if latency < 100ms
+//	test.Less(t, 10, 10) // Fails
+func Less[T cmp.Ordered](tb testing.TB, got, want T, options ...Option) {
+	tb.Helper()
+	order(tb, "Less", "Not Less", got, want, cmp.Compare[T], less, options)
+}
+
+// LessOrEqual fails if got is greater than want.
+//
+//	test.LessOrEqual(t, 10, 10) // Passes
+//	test.LessOrEqual(t, 11, 10) // Fails
+func LessOrEqual[T cmp.Ordered](tb testing.TB, got, want T, options ...Option) {
+	tb.Helper()
+	order(tb, "LessOrEqual", "Not LessOrEqual", got, want, cmp.Compare[T], lessOrEqual, options)
+}
+
+// Greater fails if got is not strictly greater than want.
+//
+//	test.Greater(t, count, 0) // Passes if count > 0
+//	test.Greater(t, 10, 10) // Fails
+func Greater[T cmp.Ordered](tb testing.TB, got, want T, options ...Option) {
+	tb.Helper()
+	order(tb, "Greater", "Not Greater", got, want, cmp.Compare[T], greater, options)
+}
+
+// GreaterOrEqual fails if got is less than want.
+//
+//	test.GreaterOrEqual(t, 10, 10) // Passes
+//	test.GreaterOrEqual(t, 9, 10) // Fails
+func GreaterOrEqual[T cmp.Ordered](tb testing.TB, got, want T, options ...Option) {
+	tb.Helper()
+	order(tb, "GreaterOrEqual", "Not GreaterOrEqual", got, want, cmp.Compare[T], greaterOrEqual, options)
+}
+
+// Between fails if got is not within the inclusive range [lower, upper], or if lower is
+// greater than upper as then no value could be.
+//
+//	test.Between(t, 5, 1, 10) // Passes
+//	test.Between(t, 10, 1, 10) // Passes, the range is inclusive
+//	test.Between(t, 11, 1, 10) // Fails
+func Between[T cmp.Ordered](tb testing.TB, got, lower, upper T, options ...Option) {
+	tb.Helper()
+	between(tb, "Between", got, lower, upper, cmp.Compare[T], options)
+}
+
+// LessFunc is like [Less] but accepts a custom comparator function, useful when the items
+// to be compared do not implement the [cmp.Ordered] generic constraint.
+//
+// The signature of the comparator is such that standard library functions such as
+// [strings.Compare] or [time.Time.Compare] can be used, it should return a negative number
+// if a < b, a positive number if a > b and zero if they are equal.
+//
+//	test.LessFunc(t, start, end, time.Time.Compare)
+func LessFunc[T any](tb testing.TB, got, want T, compare func(a, b T) int, options ...Option) {
+	tb.Helper()
+	order(tb, "LessFunc", "Not Less", got, want, compare, less, options)
+}
+
+// LessOrEqualFunc is like [LessOrEqual] but accepts a custom comparator function, see
+// [LessFunc] for details.
+//
+//	test.LessOrEqualFunc(t, got, deadline, time.Time.Compare)
+func LessOrEqualFunc[T any](tb testing.TB, got, want T, compare func(a, b T) int, options ...Option) {
+	tb.Helper()
+	order(tb, "LessOrEqualFunc", "Not LessOrEqual", got, want, compare, lessOrEqual, options)
+}
+
+// GreaterFunc is like [Greater] but accepts a custom comparator function, see
+// [LessFunc] for details.
+//
+//	test.GreaterFunc(t, newVersion, oldVersion, semver.Compare)
+func GreaterFunc[T any](tb testing.TB, got, want T, compare func(a, b T) int, options ...Option) {
+	tb.Helper()
+	order(tb, "GreaterFunc", "Not Greater", got, want, compare, greater, options)
+}
+
+// GreaterOrEqualFunc is like [GreaterOrEqual] but accepts a custom comparator function, see
+// [LessFunc] for details.
+//
+//	test.GreaterOrEqualFunc(t, got, minVersion, semver.Compare)
+func GreaterOrEqualFunc[T any](tb testing.TB, got, want T, compare func(a, b T) int, options ...Option) {
+	tb.Helper()
+	order(tb, "GreaterOrEqualFunc", "Not GreaterOrEqual", got, want, compare, greaterOrEqual, options)
+}
+
+// BetweenFunc is like [Between] but accepts a custom comparator function, see
+// [LessFunc] for details.
+//
+//	test.BetweenFunc(t, modified, start, end, time.Time.Compare)
+func BetweenFunc[T any](tb testing.TB, got, lower, upper T, compare func(a, b T) int, options ...Option) {
+	tb.Helper()
+	between(tb, "BetweenFunc", got, lower, upper, compare, options)
+}
+
+// Sorted fails if s is not sorted in ascending order, reporting the first pair of elements
+// that are out of order along with their neighbours. Equal neighbouring elements are allowed,
+// see [StrictlySorted] if they shouldn't be.
+//
+//	test.Sorted(t, []int{1, 2, 2, 3}) // Passes
+//	test.Sorted(t, []int{1, 3, 2}) // Fails
+func Sorted[T cmp.Ordered](tb testing.TB, s []T, options ...Option) {
+	tb.Helper()
+	sorted(tb, "Sorted", s, cmp.Compare[T], false, options)
+}
+
+// StrictlySorted is like [Sorted] but also fails if any neighbouring elements are equal,
+// useful for asserting that results are both sorted and unique.
+//
+//	test.StrictlySorted(t, []int{1, 2, 3}) // Passes
+//	test.StrictlySorted(t, []int{1, 2, 2, 3}) // Fails
+func StrictlySorted[T cmp.Ordered](tb testing.TB, s []T, options ...Option) {
+	tb.Helper()
+	sorted(tb, "StrictlySorted", s, cmp.Compare[T], true, options)
+}
+
+// SortedFunc is like [Sorted] but accepts a custom comparator function, in the same
+// way as [slices.SortFunc], see [LessFunc] for details.
+//
+//	test.SortedFunc(t, users, func(a, b User) int { return strings.Compare(a.Name, b.Name) })
+func SortedFunc[T any](tb testing.TB, s []T, compare func(a, b T) int, options ...Option) {
+	tb.Helper()
+	sorted(tb, "SortedFunc", s, compare, false, options)
+}
+
+// StrictlySortedFunc is like [StrictlySorted] but accepts a custom comparator function, in
+// the same way as [slices.SortFunc], see [LessFunc] for details.
+//
+//	test.StrictlySortedFunc(t, events, func(a, b Event) int { return a.ID - b.ID })
+func StrictlySortedFunc[T any](tb testing.TB, s []T, compare func(a, b T) int, options ...Option) {
+	tb.Helper()
+	sorted(tb, "StrictlySortedFunc", s, compare, true, options)
+}
+
+// order implements the ordering assertions, failing if rel does not hold between got and want
+// according to compare. The name of the calling assertion is used in any option errors.
+func order[T any](tb testing.TB, name, title string, got, want T, compare func(a, b T) int, rel relation, options []Option) {
+	tb.Helper()
+
+	cfg := defaultConfig(tb)
+	cfg.title = title
+
+	for _, option := range options {
+		if err := option.apply(&cfg); err != nil {
+			tb.Fatalf("%s: could not apply options: %v", name, err)
+
+			return
+		}
+	}
+
+	if c := compare(got, want); !rel.holds(c) {
+		cfg.reason = fmt.Sprintf("Expected got %s want, but got %s want", rel.symbol, actualRelation(c))
+		fail := failure[T]{
+			got:  got,
+			want: want,
+			cfg:  cfg,
+		}
+		tb.Fatal(fail.String())
+	}
+}
+
+// between implements [Between] and [BetweenFunc], the name of the calling assertion
+// is used in any option errors.
+func between[T any](tb testing.TB, name string, got, lower, upper T, compare func(a, b T) int, options []Option) {
+	tb.Helper()
+
+	cfg := defaultConfig(tb)
+	cfg.title = "Not Between"
+
+	for _, option := range options {
+		if err := option.apply(&cfg); err != nil {
+			tb.Fatalf("%s: could not apply options: %v", name, err)
+
+			return
+		}
+	}
+
+	if compare(lower, upper) > 0 {
+		tb.Fatalf("%s: invalid range, lower is greater than upper: %s > %s", name, cfg.format(lower), cfg.format(upper))
+
+		return
+	}
+
+	switch {
+	case compare(got, lower) < 0:
+		cfg.reason = "Expected lower <= got <= upper, but got < lower"
+	case compare(got, upper) > 0:
+		cfg.reason = "Expected lower <= got <= upper, but got > upper"
+	default:
+		return
+	}
+
+	s := &strings.Builder{}
+	cfg.writeHeader(s)
+
+	fmt.Fprintf(s, "Got:\t%s\n", cfg.format(got))
+	fmt.Fprintf(s, "Lower:\t%s\n", cfg.format(lower))
+	fmt.Fprintf(s, "Upper:\t%s\n", cfg.format(upper))
+
+	cfg.writeFooter(s)
+	tb.Fatal(s.String())
+}
+
+// sorted implements the sorted assertions, if strict is true then neighbouring elements
+// must not compare equal. The name of the calling assertion is used in any option errors.
+func sorted[T any](tb testing.TB, name string, s []T, compare func(a, b T) int, strict bool, options []Option) {
+	tb.Helper()
+
+	cfg := defaultConfig(tb)
+	cfg.title = "Not Sorted"
+
+	if strict {
+		cfg.title = "Not Strictly Sorted"
+	}
+
+	for _, option := range options {
+		if err := option.apply(&cfg); err != nil {
+			tb.Fatalf("%s: could not apply options: %v", name, err)
+
+			return
+		}
+	}
+
+	for i := range len(s) - 1 {
+		c := compare(s[i], s[i+1])
+		if c < 0 || (c == 0 && !strict) {
+			continue
+		}
+
+		problem := "out of order"
+		if c == 0 {
+			problem = "equal"
+		}
+
+		cfg.reason = fmt.Sprintf(
+			"Elements at index %d and %d are %s: %s %s %s",
+			i,
+			i+1,
+			problem,
+			cfg.format(s[i]),
+			actualRelation(c),
+			cfg.format(s[i+1]),
+		)
+
+		out := &strings.Builder{}
+		cfg.writeHeader(out)
+		writeSortedWindow(out, s, i, cfg)
+		cfg.writeFooter(out)
+		tb.Fatal(out.String())
+
+		return
+	}
+}
+
+// writeSortedWindow writes the elements of s surrounding the out of order pair at
+// index i and i+1 to out, one per line with their index and the pair marked with ">".
+func writeSortedWindow[T any](out *strings.Builder, s []T, i int, cfg config) {
+	start := max(0, i-sortedWindow)
+	end := min(len(s), i+2+sortedWindow)
+
+	fmt.Fprintf(out, "Got:\t%s\n\n", plural(len(s), "element"))
+
+	tw := tabwriter.NewWriter(out, 0, 0, 1, ' ', 0)
+
+	if start > 0 {
+		fmt.Fprintln(tw, "  ...")
+	}
+
+	for index := start; index < end; index++ {
+		marker := " "
+		if index == i || index == i+1 {
+			marker = ">"
+		}
+
+		fmt.Fprintf(tw, "%s [%d]\t%s\n", marker, index, cfg.format(s[index]))
+	}
+
+	if end < len(s) {
+		fmt.Fprintln(tw, "  ...")
+	}
+
+	tw.Flush()
+}
+
+// actualRelation returns the symbol for the relation that actually holds between
+// two values, given the result of comparing them.
+func actualRelation(c int) string {
+	switch {
+	case c < 0:
+		return "<"
+	case c > 0:
+		return ">"
+	default:
+		return "=="
+	}
+}
@@ -0,0 +1,214 @@
+package test
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// HasPrefix fails if got does not start with prefix.
+//
+//	test.HasPrefix(t, "usage: demo [flags]", "usage:") // Passes
+//	test.HasPrefix(t, "Usage: demo [flags]", "usage:") // Fails
+func HasPrefix(tb testing.TB, got, prefix string, options ...Option) {
+	tb.Helper()
+
+	cfg := defaultConfig(tb)
+	cfg.title = "Missing Prefix"
+
+	for _, option := range options {
+		if err := option.apply(&cfg); err != nil {
+			tb.Fatalf("HasPrefix: could not apply options: %v", err)
+
+			return
+		}
+	}
+
+	if strings.HasPrefix(got, prefix) {
+		return
+	}
+
+	if len(got) < len(prefix) && strings.HasPrefix(prefix, got) {
+		cfg.reason = fmt.Sprintf("got (%s) is shorter than prefix (%s)", plural(len(got), "byte"), plural(len(prefix), "byte"))
+	} else {
+		offset := firstDifference([]byte(got), []byte(prefix))
+		cfg.reason = fmt.Sprintf("got does not start with prefix, first difference at byte %d", offset)
+	}
+
+	tb.Fatal(stringFailure(cfg, got, "Prefix", prefix, focusDifference))
+}
+
+// HasSuffix fails if got does not end with suffix.
+//
+//	test.HasSuffix(t, "report.json", ".json") // Passes
+//	test.HasSuffix(t, "report.yaml", ".json") // Fails
+func HasSuffix(tb testing.TB, got, suffix string, options ...Option) {
+	tb.Helper()
+
+	cfg := defaultConfig(tb)
+	cfg.title = "Missing Suffix"
+
+	for _, option := range options {
+		if err := option.apply(&cfg); err != nil {
+			tb.Fatalf("HasSuffix: could not apply options: %v", err)
+
+			return
+		}
+	}
+
+	if strings.HasSuffix(got, suffix) {
+		return
+	}
+
+	if len(got) < len(suffix) && strings.HasSuffix(suffix, got) {
+		cfg.reason = fmt.Sprintf("got (%s) is shorter than suffix (%s)", plural(len(got), "byte"), plural(len(suffix), "byte"))
+	} else {
+		cfg.reason = "got does not end with suffix"
+	}
+
+	tb.Fatal(stringFailure(cfg, got, "Suffix", suffix, focusEnd))
+}
+
+// ContainsSubstring fails if got does not contain substr.
+//
+//	test.ContainsSubstring(t, stdout, "usage") // Passes if "usage" is anywhere in stdout
+//	test.ContainsSubstring(t, "hello", "goodbye") // Fails
+func ContainsSubstring(tb testing.TB, got, substr string, options ...Option) {
+	tb.Helper()
+
+	cfg := defaultConfig(tb)
+	cfg.title = "Missing Substring"
+
+	for _, option := range options {
+		if err := option.apply(&cfg); err != nil {
+			tb.Fatalf("ContainsSubstring: could not apply options: %v", err)
+
+			return
+		}
+	}
+
+	if strings.Contains(got, substr) {
+		return
+	}
+
+	cfg.reason = "got does not contain substring"
+
+	tb.Fatal(stringFailure(cfg, got, "Substring", substr, focusStart))
+}
+
+// Matches fails if got does not match the regular expression pattern, using the
+// syntax accepted by [regexp.Compile]. Like [regexp.MatchString], pattern may match
+// anywhere in got unless it is anchored with ^ and $.
+//
+// If only part of the pattern matches, the failure shows the longest leading part of
+// the pattern that does and the text it matched, which is usually enough to spot where
+// got went wrong.
+//
+//	test.Matches(t, "v1.2.3", `^v\d+\.\d+\.\d+$`) // Passes
+//	test.Matches(t, "v1.2", `^v\d+\.\d+\.\d+$`) // Fails
+func Matches(tb testing.TB, got, pattern string, options ...Option) {
+	tb.Helper()
+
+	cfg := defaultConfig(tb)
+	cfg.title = "No Match"
+
+	for _, option := range options {
+		if err := option.apply(&cfg); err != nil {
+			tb.Fatalf("Matches: could not apply options: %v", err)
+
+			return
+		}
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		tb.Fatalf("Matches: invalid pattern: %v", err)
+
+		return
+	}
+
+	if re.MatchString(got) {
+		return
+	}
+
+	cfg.reason = matchReason("got", got, pattern)
+
+	tb.Fatal(stringFailure(cfg, got, "Pattern", verbatim(pattern), focusStart))
+}
+
+// stringFocus is the part of a long value kept in view when it is cut down for a string failure.
+type stringFocus int
+
+const (
+	focusStart      stringFocus = iota // The beginning, where there's no one place that matters more
+	focusDifference                    // The first difference between got and want, e.g. from a prefix
+	focusEnd                           // The end, e.g. where a suffix should be
+)
+
+// stringFailure renders the failure for the string assertions, showing got alongside
+// want under label. Long values are cut down so that the part of them given by focus
+// stays in view.
+func stringFailure(cfg config, got, label string, want any, focus stringFocus) string {
+	s := &strings.Builder{}
+	cfg.writeHeader(s)
+
+	gotText, wantText := cfg.format(got), cfg.format(want)
+
+	// Offsets are found in the formatted text, as quoting escapes some characters to
+	// several bytes and a formatter may change it completely
+	offset := 0
+
+	switch focus {
+	case focusStart:
+	case focusDifference:
+		offset = max(0, firstDifference([]byte(gotText), []byte(wantText)))
+	case focusEnd:
+		offset = math.MaxInt
+	}
+
+	fmt.Fprintf(s, "Got:\t%s\n", truncateValue(gotText, offset))
+	fmt.Fprintf(s, "%s:\t%s\n", label, truncateValue(wantText, offset))
+
+	cfg.writeFooter(s)
+
+	return s.String()
+}
+
+// matchReason explains why text, referred to as subject, does not match pattern, including
+// the longest leading part of the pattern that does match if there is one.
+func matchReason(subject, text, pattern string) string {
+	partial, matched, ok := longestMatch(text, pattern)
+	if !ok {
+		return subject + " does not match pattern"
+	}
+
+	return fmt.Sprintf(
+		"%s does not match pattern, the longest leading part of the pattern that matches is %s, matching %q",
+		subject,
+		partial,
+		matched,
+	)
+}
+
+// longestMatch finds the longest leading part of pattern that matches some non-empty
+// part of got, returning that part of the pattern and the text of got it matched.
+//
+// Shorter and shorter prefixes of the pattern are tried in turn, skipping any that are not
+// valid expressions on their own e.g. ending part way through a group. If none of the
+// pattern matches, ok is false.
+func longestMatch(got, pattern string) (partial, matched string, ok bool) {
+	for n := len(pattern) - 1; n > 0; n-- {
+		re, err := regexp.Compile(pattern[:n])
+		if err != nil {
+			continue
+		}
+
+		if loc := re.FindStringIndex(got); loc != nil && loc[1] > loc[0] {
+			return pattern[:n], got[loc[0]:loc[1]], true
+		}
+	}
+
+	return "", "", false
+}
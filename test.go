@@ -9,11 +9,15 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"math"
 	"os"
+	"path/filepath"
 	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"go.followtheprocess.codes/diff"
 	"go.followtheprocess.codes/diff/render"
@@ -44,10 +48,13 @@ func ColorEnabled(v bool) {
 //
 //	test.Equal(t, "apples", "apples") // Passes
 //	test.Equal(t, "apples", "oranges") // Fails
+//
+// If got and want are strings and either spans multiple lines, the failure shows a unified
+// diff of the two as in [Diff].
 func Equal[T comparable](tb testing.TB, got, want T, options ...Option) {
 	tb.Helper()
 
-	cfg := defaultConfig()
+	cfg := defaultConfig(tb)
 	cfg.title = "Not Equal"
 
 	for _, option := range options {
@@ -59,10 +66,19 @@ func Equal[T comparable](tb testing.TB, got, want T, options ...Option) {
 	}
 
 	if got != want {
+		if printsIdentically(got, want, cfg) {
+			cfg.hints = append(
+				cfg.hints,
+				"got and want print identically but are not equal, they may differ in type or hold different pointers, "+
+					"consider EqualFunc with a custom comparison",
+			)
+		}
+
 		fail := failure[T]{
-			got:  got,
-			want: want,
-			cfg:  cfg,
+			got:       got,
+			want:      want,
+			cfg:       cfg,
+			diffLines: true,
 		}
 		tb.Fatal(fail.String())
 	}
@@ -72,10 +88,13 @@ func Equal[T comparable](tb testing.TB, got, want T, options ...Option) {
 //
 //	test.NotEqual(t, 10, 42) // Passes
 //	test.NotEqual(t, 42, 42) // Fails
+//
+// If got and want are strings spanning multiple lines, the failure shows their text once
+// rather than each on a single line.
 func NotEqual[T comparable](tb testing.TB, got, want T, options ...Option) {
 	tb.Helper()
 
-	cfg := defaultConfig()
+	cfg := defaultConfig(tb)
 	cfg.title = "Equal"
 
 	for _, option := range options {
@@ -88,9 +107,10 @@ func NotEqual[T comparable](tb testing.TB, got, want T, options ...Option) {
 
 	if got == want {
 		fail := failure[T]{
-			got:  got,
-			want: want,
-			cfg:  cfg,
+			got:       got,
+			want:      want,
+			cfg:       cfg,
+			diffLines: true,
 		}
 		tb.Fatal(fail.String())
 	}
@@ -109,7 +129,7 @@ func NotEqual[T comparable](tb testing.TB, got, want T, options ...Option) {
 func EqualFunc[T any](tb testing.TB, got, want T, equal func(a, b T) bool, options ...Option) {
 	tb.Helper()
 
-	cfg := defaultConfig()
+	cfg := defaultConfig(tb)
 	cfg.title = "Not Equal"
 
 	for _, option := range options {
@@ -144,7 +164,7 @@ func EqualFunc[T any](tb testing.TB, got, want T, equal func(a, b T) bool, optio
 func NotEqualFunc[T any](tb testing.TB, got, want T, equal func(a, b T) bool, options ...Option) {
 	tb.Helper()
 
-	cfg := defaultConfig()
+	cfg := defaultConfig(tb)
 	cfg.title = "Equal"
 
 	for _, option := range options {
@@ -169,14 +189,19 @@ func NotEqualFunc[T any](tb testing.TB, got, want T, equal func(a, b T) bool, op
 // NearlyEqual is like [Equal] but for floating point numbers where absolute equality often fails.
 //
 // If the difference between got and want is sufficiently small, they are considered equal. This threshold
-// defaults to 1e-8 but can be configured with the [FloatEqualityThreshold] option.
+// defaults to 1e-8 but can be configured with the [FloatEqualityThreshold] option, or replaced with a
+// relative or ULP based tolerance with the [RelativeTolerance], [ULPTolerance] and [AbsOrRelTolerance] options.
+//
+// Whatever the tolerance, an infinity is only nearly equal to an infinity of the same sign
+// and NaN is never nearly equal to anything, unless the [NaNEqual] option is used in which
+// case NaN is nearly equal to NaN.
 //
 //	test.NearlyEqual(t, 3.0000000001, 3.0) // Passes, close enough to be considered equal
 //	test.NearlyEqual(t, 3.0000001, 3.0) // Fails, too different
 func NearlyEqual[T ~float32 | ~float64](tb testing.TB, got, want T, options ...Option) {
 	tb.Helper()
 
-	cfg := defaultConfig()
+	cfg := defaultConfig(tb)
 	cfg.title = "Not NearlyEqual"
 
 	for _, option := range options {
@@ -187,15 +212,8 @@ func NearlyEqual[T ~float32 | ~float64](tb testing.TB, got, want T, options ...O
 		}
 	}
 
-	delta := math.Abs(float64(got - want))
-	if delta > cfg.floatEqualityThreshold {
-		cfg.reason = fmt.Sprintf(
-			"Difference %v - %v = %v exceeds maximum tolerance of %v",
-			got,
-			want,
-			delta,
-			cfg.floatEqualityThreshold,
-		)
+	if equal, reason := compareFloats(got, want, cfg); !equal {
+		cfg.reason = reason
 		fail := failure[T]{
 			got:  got,
 			want: want,
@@ -208,15 +226,15 @@ func NearlyEqual[T ~float32 | ~float64](tb testing.TB, got, want T, options ...O
 // NotNearlyEqual is the opposite of [NearlyEqual]. It fails when got and want
 // are within the float equality threshold of each other.
 //
-// The threshold defaults to 1e-8 and can be configured with the
-// [FloatEqualityThreshold] option.
+// The threshold defaults to 1e-8 and can be configured with the [FloatEqualityThreshold]
+// option, or replaced with any of the other tolerances described in [NearlyEqual].
 //
 //	test.NotNearlyEqual(t, 3.0000001, 3.0) // Passes, different enough
 //	test.NotNearlyEqual(t, 3.0000000001, 3.0) // Fails, too close to be considered different
 func NotNearlyEqual[T ~float32 | ~float64](tb testing.TB, got, want T, options ...Option) {
 	tb.Helper()
 
-	cfg := defaultConfig()
+	cfg := defaultConfig(tb)
 	cfg.title = "NearlyEqual"
 
 	for _, option := range options {
@@ -227,15 +245,8 @@ func NotNearlyEqual[T ~float32 | ~float64](tb testing.TB, got, want T, options .
 		}
 	}
 
-	delta := math.Abs(float64(got - want))
-	if delta <= cfg.floatEqualityThreshold {
-		cfg.reason = fmt.Sprintf(
-			"Difference %v - %v = %v is within tolerance of %v",
-			got,
-			want,
-			delta,
-			cfg.floatEqualityThreshold,
-		)
+	if equal, reason := compareFloats(got, want, cfg); equal {
+		cfg.reason = reason
 		fail := failure[T]{
 			got:  got,
 			want: want,
@@ -252,7 +263,7 @@ func NotNearlyEqual[T ~float32 | ~float64](tb testing.TB, got, want T, options .
 func Ok(tb testing.TB, err error, options ...Option) {
 	tb.Helper()
 
-	cfg := defaultConfig()
+	cfg := defaultConfig(tb)
 	cfg.title = "Not Ok"
 
 	for _, option := range options {
@@ -265,9 +276,10 @@ func Ok(tb testing.TB, err error, options ...Option) {
 
 	if err != nil {
 		fail := failure[error]{
-			got:  err,
-			want: nil,
-			cfg:  cfg,
+			got:      err,
+			want:     nil,
+			cfg:      cfg,
+			sections: errorChainSection(err),
 		}
 		tb.Fatal(fail.String())
 	}
@@ -280,7 +292,7 @@ func Ok(tb testing.TB, err error, options ...Option) {
 func Err(tb testing.TB, err error, options ...Option) {
 	tb.Helper()
 
-	cfg := defaultConfig()
+	cfg := defaultConfig(tb)
 	cfg.title = "Not Err"
 
 	for _, option := range options {
@@ -308,7 +320,7 @@ func Err(tb testing.TB, err error, options ...Option) {
 func ErrorIs(tb testing.TB, err, target error, options ...Option) {
 	tb.Helper()
 
-	cfg := defaultConfig()
+	cfg := defaultConfig(tb)
 	cfg.title = "Wrong Error"
 
 	for _, option := range options {
@@ -321,9 +333,10 @@ func ErrorIs(tb testing.TB, err, target error, options ...Option) {
 
 	if !errors.Is(err, target) {
 		fail := failure[error]{
-			got:  err,
-			want: target,
-			cfg:  cfg,
+			got:      err,
+			want:     target,
+			cfg:      cfg,
+			sections: errorChainSection(err),
 		}
 		tb.Fatal(fail.String())
 	}
@@ -346,7 +359,7 @@ func ErrorIs(tb testing.TB, err, target error, options ...Option) {
 func ErrorAs[T error](tb testing.TB, err error, options ...Option) T {
 	tb.Helper()
 
-	cfg := defaultConfig()
+	cfg := defaultConfig(tb)
 	cfg.title = "Wrong Error Type"
 
 	for _, option := range options {
@@ -368,10 +381,11 @@ func ErrorAs[T error](tb testing.TB, err error, options ...Option) T {
 		got = fmt.Sprintf("%T: %s", err, err.Error())
 	}
 
-	fail := failure[string]{
-		got:  got,
-		want: fmt.Sprintf("error matching %s", reflect.TypeFor[T]()),
-		cfg:  cfg,
+	fail := failure[verbatim]{
+		got:      verbatim(got),
+		want:     verbatim(fmt.Sprintf("error matching %s", reflect.TypeFor[T]())),
+		cfg:      cfg,
+		sections: errorChainSection(err),
 	}
 	tb.Fatal(fail.String())
 
@@ -380,6 +394,94 @@ func ErrorAs[T error](tb testing.TB, err error, options ...Option) T {
 	return zero
 }
 
+// ErrorContains fails if err is nil or its message does not contain substr.
+//
+// This is useful when an error comes from a library that exposes neither a sentinel
+// for [ErrorIs] nor a type for [ErrorAs], prefer those where possible as error messages
+// are more likely to change.
+//
+//	test.ErrorContains(t, err, "permission denied")
+func ErrorContains(tb testing.TB, err error, substr string, options ...Option) {
+	tb.Helper()
+
+	cfg := defaultConfig(tb)
+	cfg.title = "Wrong Error Message"
+
+	for _, option := range options {
+		if optionErr := option.apply(&cfg); optionErr != nil {
+			tb.Fatalf("ErrorContains: could not apply options: %v", optionErr)
+
+			return
+		}
+	}
+
+	if err == nil {
+		cfg.reason = fmt.Sprintf("Wanted an error containing %q but got <nil>", substr)
+		fail := failure[error]{
+			got:  nil,
+			want: errAny,
+			cfg:  cfg,
+		}
+		tb.Fatal(fail.String())
+
+		return
+	}
+
+	if strings.Contains(err.Error(), substr) {
+		return
+	}
+
+	cfg.reason = "error message does not contain substring"
+
+	tb.Fatal(stringFailure(cfg, err.Error(), "Substring", substr, focusStart))
+}
+
+// ErrorMatches fails if err is nil or its message does not match the regular expression
+// pattern, using the syntax accepted by [regexp.Compile]. See [Matches] for details.
+//
+//	test.ErrorMatches(t, err, `^open .+: no such file or directory$`)
+func ErrorMatches(tb testing.TB, err error, pattern string, options ...Option) {
+	tb.Helper()
+
+	cfg := defaultConfig(tb)
+	cfg.title = "Wrong Error Message"
+
+	for _, option := range options {
+		if optionErr := option.apply(&cfg); optionErr != nil {
+			tb.Fatalf("ErrorMatches: could not apply options: %v", optionErr)
+
+			return
+		}
+	}
+
+	re, reErr := regexp.Compile(pattern)
+	if reErr != nil {
+		tb.Fatalf("ErrorMatches: invalid pattern: %v", reErr)
+
+		return
+	}
+
+	if err == nil {
+		cfg.reason = fmt.Sprintf("Wanted an error matching %s but got <nil>", pattern)
+		fail := failure[error]{
+			got:  nil,
+			want: errAny,
+			cfg:  cfg,
+		}
+		tb.Fatal(fail.String())
+
+		return
+	}
+
+	if re.MatchString(err.Error()) {
+		return
+	}
+
+	cfg.reason = matchReason("error message", err.Error(), pattern)
+
+	tb.Fatal(stringFailure(cfg, err.Error(), "Pattern", verbatim(pattern), focusStart))
+}
+
 // WantErr fails if you got an error and didn't want it, or if you didn't
 // get an error but wanted one.
 //
@@ -393,7 +495,7 @@ func ErrorAs[T error](tb testing.TB, err error, options ...Option) T {
 func WantErr(tb testing.TB, err error, want bool, options ...Option) {
 	tb.Helper()
 
-	cfg := defaultConfig()
+	cfg := defaultConfig(tb)
 	cfg.title = "WantErr"
 
 	for _, option := range options {
@@ -405,29 +507,139 @@ func WantErr(tb testing.TB, err error, want bool, options ...Option) {
 	}
 
 	if (err != nil) != want {
-		var (
-			reason string
-			wanted error
-		)
+		tb.Fatal(wantErrMismatch(err, errAny, cfg))
+	}
+}
 
-		if want {
-			reason = fmt.Sprintf("Wanted an error but got %v", err)
-			wanted = errAny
-		} else {
-			reason = fmt.Sprintf("Got an unexpected error: %v", err)
-			wanted = nil
+// WantErrIs is like [WantErr] but also checks which error was returned, failing if err
+// does not match target as reported by [errors.Is]. A nil target means no error is wanted.
+//
+// It is designed for table driven tests where each case declares the error it expects:
+//
+//	tests := []struct {
+//		name    string
+//		input   string
+//		wantErr error
+//	}{
+//		{name: "valid", input: "ok", wantErr: nil},
+//		{name: "empty", input: "", wantErr: ErrEmpty},
+//	}
+//
+//	for _, tt := range tests {
+//		t.Run(tt.name, func(t *testing.T) {
+//			err := validate(tt.input)
+//			test.WantErrIs(t, err, tt.wantErr)
+//		})
+//	}
+func WantErrIs(tb testing.TB, err, target error, options ...Option) {
+	tb.Helper()
+
+	cfg := defaultConfig(tb)
+	cfg.title = "WantErr"
+
+	for _, option := range options {
+		if optionErr := option.apply(&cfg); optionErr != nil {
+			tb.Fatalf("WantErrIs: could not apply options: %v", optionErr)
+
+			return
 		}
+	}
 
-		cfg.reason = reason
+	if (err != nil) != (target != nil) {
+		tb.Fatal(wantErrMismatch(err, target, cfg))
+
+		return
+	}
+
+	if err != nil && !errors.Is(err, target) {
+		cfg.reason = "Got an error but not the one wanted, errors.Is(err, target) is false"
 		fail := failure[error]{
-			got:  err,
-			want: wanted,
-			cfg:  cfg,
+			got:      err,
+			want:     target,
+			cfg:      cfg,
+			sections: errorChainSection(err),
 		}
 		tb.Fatal(fail.String())
 	}
 }
 
+// WantErrAs is like [WantErr] but when an error is wanted, also checks that err or some error
+// in its chain matches the concrete type T as reported by [errors.AsType]. If want is false,
+// it fails if err is not nil.
+//
+// Like [ErrorAs], the matched error is returned so further assertions can be made on it,
+// the zero value of T is returned if no error was wanted.
+//
+//	pathErr := test.WantErrAs[*os.PathError](t, err, tt.wantErr)
+func WantErrAs[T error](tb testing.TB, err error, want bool, options ...Option) T {
+	tb.Helper()
+
+	var zero T
+
+	cfg := defaultConfig(tb)
+	cfg.title = "WantErr"
+
+	for _, option := range options {
+		if optionErr := option.apply(&cfg); optionErr != nil {
+			tb.Fatalf("WantErrAs: could not apply options: %v", optionErr)
+
+			return zero
+		}
+	}
+
+	if (err != nil) != want {
+		tb.Fatal(wantErrMismatch(err, fmt.Errorf("<error matching %s>", reflect.TypeFor[T]()), cfg))
+
+		return zero
+	}
+
+	if err == nil {
+		return zero
+	}
+
+	if target, ok := errors.AsType[T](err); ok {
+		return target
+	}
+
+	cfg.reason = fmt.Sprintf("Got an error but not the one wanted, no error in the chain matches %s", reflect.TypeFor[T]())
+	fail := failure[verbatim]{
+		got:      verbatim(fmt.Sprintf("%T: %s", err, err.Error())),
+		want:     verbatim(fmt.Sprintf("error matching %s", reflect.TypeFor[T]())),
+		cfg:      cfg,
+		sections: errorChainSection(err),
+	}
+	tb.Fatal(fail.String())
+
+	return zero
+}
+
+// printsIdentically reports whether got and want look the same in the failure log,
+// in which case the failure alone doesn't show why they differ.
+func printsIdentically(got, want any, cfg config) bool {
+	return cfg.format(got) == cfg.format(want)
+}
+
+// wantErrMismatch renders the failure for the WantErr family of assertions when
+// an error was returned but not wanted, or wanted but not returned. The wanted
+// error is shown as what was expected in the latter case.
+func wantErrMismatch(err, wanted error, cfg config) string {
+	if err == nil {
+		cfg.reason = fmt.Sprintf("Wanted an error but got %v", err)
+	} else {
+		cfg.reason = fmt.Sprintf("Got an unexpected error: %v", err)
+		wanted = nil
+	}
+
+	fail := failure[error]{
+		got:      err,
+		want:     wanted,
+		cfg:      cfg,
+		sections: errorChainSection(err),
+	}
+
+	return fail.String()
+}
+
 // True fails if got is false.
 //
 //	test.True(t, true) // Passes
@@ -435,7 +647,7 @@ func WantErr(tb testing.TB, err error, want bool, options ...Option) {
 func True(tb testing.TB, got bool, options ...Option) {
 	tb.Helper()
 
-	cfg := defaultConfig()
+	cfg := defaultConfig(tb)
 	cfg.title = "Not True"
 
 	for _, option := range options {
@@ -463,7 +675,7 @@ func True(tb testing.TB, got bool, options ...Option) {
 func False(tb testing.TB, got bool, options ...Option) {
 	tb.Helper()
 
-	cfg := defaultConfig()
+	cfg := defaultConfig(tb)
 	cfg.title = "Not False"
 
 	for _, option := range options {
@@ -499,10 +711,18 @@ func Diff(tb testing.TB, got, want string, options ...Option) {
 //
 // If either got or want do not end in a newline, one is added to avoid a
 // "No newline at end of file" warning in the diff which is visually distracting.
+//
+// If either got or want is binary (it is not valid UTF-8 or contains a NUL byte), a
+// line diff would be meaningless so instead the differing regions of both are shown in
+// an aligned hex dump, along with the offset of the first difference and the length of each.
+//
+// For very large inputs, the amount of diff shown can be controlled with the
+// [DiffContextLines], [MaxDiffHunks] and [MaxDiffLines] options, or the whole
+// diff can be written to a file instead with [SaveDiff].
 func DiffBytes(tb testing.TB, got, want []byte, options ...Option) {
 	tb.Helper()
 
-	cfg := defaultConfig()
+	cfg := defaultConfig(tb)
 	cfg.title = "Diff"
 
 	for _, option := range options {
@@ -513,18 +733,106 @@ func DiffBytes(tb testing.TB, got, want []byte, options ...Option) {
 		}
 	}
 
+	diffBytes(tb, "DiffBytes", got, want, cfg)
+}
+
+// diffBytes implements [DiffBytes] with an already applied config, so that other
+// assertions can share the diff rendering, name is the assertion used in any
+// internal error messages.
+func diffBytes(tb testing.TB, name string, got, want []byte, cfg config) {
+	tb.Helper()
+
+	rendered := renderDiff("want", "got", got, want, cfg)
+	if rendered == nil {
+		return
+	}
+
+	reportDiff(tb, name, rendered, cfg)
+}
+
+// reportDiff fails the test with rendered, a diff returned by [renderDiff], saving it
+// to a file instead if cfg has [SaveDiff] set. Name is the assertion used in any internal
+// error messages.
+func reportDiff(tb testing.TB, name string, rendered []byte, cfg config) {
+	tb.Helper()
+
+	s := &strings.Builder{}
+	cfg.writeHeader(s)
+
+	if cfg.saveDiff != "" {
+		path, err := saveDiff(tb, cfg, rendered)
+		if err != nil {
+			tb.Fatalf("%s: could not save diff: %v", name, err)
+
+			return
+		}
+
+		fmt.Fprintf(s, "Diff written to %s\n", path)
+	} else {
+		s.Write(rendered)
+	}
+
+	cfg.writeFooter(s)
+	tb.Fatal(s.String())
+}
+
+// renderDiff returns the diff between want and got, labelled with wantName and gotName,
+// or nil if they are equal.
+//
+// If cfg has [SaveDiff] set, the diff is returned plain and in full ready to be written
+// to a file, otherwise it is coloured and truncated according to cfg.
+func renderDiff(wantName, gotName string, got, want []byte, cfg config) []byte {
+	rendered, _ := renderDiffHunks(wantName, gotName, got, want, cfg)
+
+	return rendered
+}
+
+// renderDiffHunks is like [renderDiff] but also returns the number of hunks shown,
+// for sharing the diff limits in cfg between several diffs.
+func renderDiffHunks(wantName, gotName string, got, want []byte, cfg config) (rendered []byte, hunks int) {
+	got = normalize(got, cfg.normalizers)
+	want = normalize(want, cfg.normalizers)
+
+	// Binary data doesn't have lines so a line diff would be meaningless, compare
+	// the raw bytes with a hex dump instead
+	if isBinary(got) || isBinary(want) {
+		if bytes.Equal(got, want) {
+			return nil, 0
+		}
+
+		if cfg.saveDiff != "" {
+			return hexDiff(got, want, false, 0), 0
+		}
+
+		return hexDiff(got, want, true, cfg.maxDiffLines), 0
+	}
+
 	got = fixNL(got)
 	want = fixNL(want)
 
-	d := diff.New("want", want, "got", got)
+	d := diff.New(wantName, want, gotName, got, diff.WithContext(cfg.diffContextLines))
+	if d.Equal() {
+		return nil, 0
+	}
+
+	if cfg.saveDiff != "" {
+		return []byte(d.String()), 0
+	}
+
+	return truncateDiff(d, render.Render(d), cfg.maxDiffHunks, cfg.maxDiffLines)
+}
+
+// saveDiff writes diff to the file set by the [SaveDiff] option in the test's
+// artifact directory, returning the path to the written file.
+func saveDiff(tb testing.TB, cfg config, diff []byte) (string, error) {
+	tb.Helper()
 
-	if !d.Equal() {
-		s := &strings.Builder{}
-		cfg.writeHeader(s)
-		s.Write(render.Render(d))
-		cfg.writeFooter(s)
-		tb.Fatal(s.String())
+	path := filepath.Join(tb.ArtifactDir(), cfg.saveDiff)
+	if err := writeFile(path, diff); err != nil {
+		return "", err
 	}
+
+	return path, nil
 }
 
 // DiffReader reads data from both got and want [io.Reader] and provides
@@ -548,6 +856,63 @@ func DiffReader(tb testing.TB, got, want io.Reader, options ...Option) {
 	DiffBytes(tb, gotData, wantData, options...)
 }
 
+// DiffLines fails if the two []string got and want are not equal and provides a rich
+// unified diff of the two for easy comparison, treating each element as a line.
+//
+// Unlike joining the elements and calling [Diff], each hunk header shows the range of
+// element indices it covers, e.g. "@@ want[3:7] got[3:8] @@", and elements containing
+// newlines can't be confused with multiple elements. If any element is not valid UTF-8 or
+// contains a character other than a tab that [strconv.IsPrint] reports as not printable, such
+// as a newline, carriage return or escape, every element is shown as a quoted Go string so the
+// diff stays unambiguous.
+//
+// The same options that control the size of the output of [DiffBytes] may be used here,
+// and [Normalize] is applied to every element.
+//
+//	got := strings.Split(output, "\n")
+//	test.DiffLines(t, got, []string{"line one", "line two"})
+func DiffLines(tb testing.TB, got, want []string, options ...Option) {
+	tb.Helper()
+
+	cfg := defaultConfig(tb)
+	cfg.title = "Diff"
+
+	for _, option := range options {
+		if err := option.apply(&cfg); err != nil {
+			tb.Fatalf("DiffLines: could not apply options: %v", err)
+
+			return
+		}
+	}
+
+	gotText, wantText := joinLines(got, want, cfg.normalizers)
+
+	d := diff.New("want", wantText, "got", gotText, diff.WithContext(cfg.diffContextLines))
+	if d.Equal() {
+		return
+	}
+
+	s := &strings.Builder{}
+	cfg.writeHeader(s)
+
+	if cfg.saveDiff != "" {
+		path, err := saveDiff(tb, cfg, relabelHunks(d, []byte(d.String()), false))
+		if err != nil {
+			tb.Fatalf("DiffLines: could not save diff: %v", err)
+
+			return
+		}
+
+		fmt.Fprintf(s, "Diff written to %s\n", path)
+	} else {
+		rendered, _ := truncateDiff(d, relabelHunks(d, render.Render(d), true), cfg.maxDiffHunks, cfg.maxDiffLines)
+		s.Write(rendered)
+	}
+
+	cfg.writeFooter(s)
+	tb.Fatal(s.String())
+}
+
 // CaptureOutput captures and returns data printed to [os.Stdout] and [os.Stderr] by the provided function fn, allowing
 // you to test functions that write to those streams and do not have an option to pass in an [io.Writer].
 //
@@ -653,6 +1018,227 @@ func copyInto(tb testing.TB, name string, r io.Reader, out chan<- string) {
 	}
 }
 
+// truncateDiff limits rendered, the rendered form of d, to at most maxHunks hunks
+// and maxLines lines, replacing anything omitted with a one line summary, and returns
+// it along with the number of hunks shown. A limit of 0 means no limit.
+//
+// The renderer emits exactly one line per line of d, although it may reorder
+// lines within a block of changes, so the hunk boundaries in d line up with
+// those in rendered.
+func truncateDiff(d diff.Diff, rendered []byte, maxHunks, maxLines int) (truncated []byte, shown int) {
+	lines := d.Lines()
+
+	out, ok := splitRendered(d, rendered)
+	if !ok {
+		// Should never happen, but better to show the whole diff than a wrong one
+		return rendered, 0
+	}
+
+	var hunks []int // Index of each hunk header line
+
+	for i, line := range lines {
+		if line.Kind == diff.KindHeader && bytes.HasPrefix(line.Content, []byte("@@")) {
+			hunks = append(hunks, i)
+		}
+	}
+
+	cut := len(lines)
+	if maxHunks > 0 && len(hunks) > maxHunks {
+		cut = hunks[maxHunks]
+	}
+
+	if maxLines > 0 && cut > maxLines {
+		cut = maxLines
+	}
+
+	if cut == len(lines) {
+		return rendered, len(hunks)
+	}
+
+	omittedHunks := 0
+	atHunk := false
+
+	for _, hunk := range hunks {
+		if hunk >= cut {
+			omittedHunks++
+		}
+
+		if hunk == cut {
+			atHunk = true
+		}
+	}
+
+	omittedLines := len(lines) - cut
+	truncated = bytes.Join(out[:cut], nil)
+
+	switch {
+	case atHunk:
+		truncated = fmt.Appendf(truncated, "\n... %s omitted\n", plural(omittedHunks, "more hunk"))
+	case omittedHunks > 0:
+		truncated = fmt.Appendf(
+			truncated,
+			"\n... %s omitted, including %s\n",
+			plural(omittedLines, "more line"),
+			plural(omittedHunks, "more hunk"),
+		)
+	default:
+		truncated = fmt.Appendf(truncated, "\n... %s omitted\n", plural(omittedLines, "more line"))
+	}
+
+	return truncated, len(hunks) - omittedHunks
+}
+
+// splitRendered splits rendered, a rendered form of d, into lines each ending in a newline,
+// reporting whether there is exactly one rendered line for each line of d.
+func splitRendered(d diff.Diff, rendered []byte) ([][]byte, bool) {
+	out := bytes.SplitAfter(rendered, []byte("\n"))
+
+	if len(out) > 0 && len(out[len(out)-1]) == 0 {
+		out = out[:len(out)-1]
+	}
+
+	return out, len(out) == len(d.Lines())
+}
+
+// joinLines joins the elements of got and want, after applying each normalizer,
+// into newline separated text ready to be diffed.
+//
+// If any element of either would be ambiguous as a line of text, every element is
+// quoted so that each one is guaranteed to be exactly one line.
+func joinLines(got, want []string, normalizers []func(data []byte) []byte) (gotText, wantText []byte) {
+	normalize := func(lines []string) []string {
+		if len(normalizers) == 0 {
+			return lines
+		}
+
+		normalized := make([]string, 0, len(lines))
+
+		for _, line := range lines {
+			data := []byte(line)
+			for _, fn := range normalizers {
+				data = fn(data)
+			}
+
+			normalized = append(normalized, string(data))
+		}
+
+		return normalized
+	}
+
+	got = normalize(got)
+	want = normalize(want)
+
+	// Tabs are common in real output and show as whitespace, so don't force quoting
+	ambiguous := func(line string) bool {
+		return !utf8.ValidString(line) || strings.ContainsFunc(line, func(r rune) bool {
+			return r != '\t' && !strconv.IsPrint(r)
+		})
+	}
+
+	quote := slices.ContainsFunc(got, ambiguous) || slices.ContainsFunc(want, ambiguous)
+
+	join := func(lines []string) []byte {
+		var text []byte
+
+		for _, line := range lines {
+			if quote {
+				text = strconv.AppendQuote(text, line)
+			} else {
+				text = append(text, line...)
+			}
+
+			text = append(text, '\n')
+		}
+
+		return text
+	}
+
+	return join(got), join(want)
+}
+
+// relabelHunks replaces each hunk header in rendered, a rendered form of d where each
+// line of d is a single element, with one showing the range of element indices the hunk
+// covers on each side. If styled is true the new headers are styled like the originals.
+func relabelHunks(d diff.Diff, rendered []byte, styled bool) []byte {
+	out, ok := splitRendered(d, rendered)
+	if !ok {
+		return rendered
+	}
+
+	for i, line := range d.Lines() {
+		if line.Kind != diff.KindHeader || !bytes.HasPrefix(line.Content, []byte("@@")) {
+			continue
+		}
+
+		// e.g. "@@ -1,4 +1,5 @@"
+		fields := strings.Fields(string(line.Content))
+		if len(fields) < 3 {
+			continue
+		}
+
+		wantStart, wantEnd := hunkRange(fields[1])
+		gotStart, gotEnd := hunkRange(fields[2])
+		header := fmt.Sprintf("@@ want[%d:%d] got[%d:%d] @@", wantStart, wantEnd, gotStart, gotEnd)
+
+		if styled {
+			out[i] = append(hue.Bold.AppendString(nil, header), '\n')
+		} else {
+			out[i] = []byte(header + "\n")
+		}
+	}
+
+	return bytes.Join(out, nil)
+}
+
+// hunkRange converts one side of a unified diff hunk header, e.g. "-3,4", into the
+// half open range of zero based line indices it covers.
+func hunkRange(field string) (start, end int) {
+	field = strings.TrimLeft(field, "-+")
+	startText, countText, found := strings.Cut(field, ",")
+
+	// The diff package always produces well formed headers
+	start, _ = strconv.Atoi(startText)
+	count := 1
+
+	if found {
+		count, _ = strconv.Atoi(countText)
+	}
+
+	if count == 0 {
+		// An empty range is given by the line before it
+		return start, start
+	}
+
+	return start - 1, start - 1 + count
+}
+
+// writeFile writes data to the file at path, creating any missing parent directories.
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
+
+// plural returns "n noun", adding an "s" to noun if n is not 1.
+func plural[N int | uint64](n N, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// normalize returns data after applying each of normalizers to it in order.
+func normalize(data []byte, normalizers []func(data []byte) []byte) []byte {
+	for _, fn := range normalizers {
+		data = fn(data)
+	}
+
+	return data
+}
+
 // If data is empty or ends in \n, fixNL returns data.
 // Otherwise fixNL returns a new slice consisting of data with a final \n added.
 func fixNL(data []byte) []byte {
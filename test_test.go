@@ -7,9 +7,14 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"math/cmplx"
 	"os"
+	"path/filepath"
 	"slices"
+	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	"go.followtheprocess.codes/snapshot"
 	"go.followtheprocess.codes/test"
@@ -25,12 +30,17 @@ var (
 type TB struct {
 	testing.TB
 
-	out    io.Writer
-	failed bool
+	out         io.Writer
+	artifactDir string
+	failed      bool
 }
 
 func (t *TB) Helper() {}
 
+func (t *TB) ArtifactDir() string {
+	return t.artifactDir
+}
+
 func (t *TB) Fatal(args ...any) {
 	t.failed = true
 	fmt.Fprint(t.out, args...)
@@ -41,6 +51,10 @@ func (t *TB) Fatalf(format string, args ...any) {
 	fmt.Fprintf(t.out, format, args...)
 }
 
+func (t *TB) Logf(format string, args ...any) {
+	fmt.Fprintf(t.out, format, args...)
+}
+
 func TestTest(t *testing.T) {
 	tests := []struct {
 		fn       func(tb testing.TB) // The test function we're... testing?
@@ -76,564 +90,2400 @@ func TestTest(t *testing.T) {
 			wantFail: true,
 		},
 		{
-			name: "Equal/fail with title",
+			name: "Equal/pass context func not called",
 			fn: func(tb testing.TB) {
-				test.Equal(tb, "apples", "oranges", test.Title("My fruit test"))
+				lazy := func() string {
+					tb.Fatal("ContextFunc called when the test passed")
+
+					return "expensive"
+				}
+				test.Equal(tb, "apples", "apples", test.ContextFunc(lazy))
+			},
+			wantFail: false,
+		},
+		{
+			name: "Equal/fail context func",
+			fn: func(tb testing.TB) {
+				test.Equal(tb, "apples", "oranges", test.ContextFunc(func() string { return "computed lazily" }))
 			},
 			wantFail: true,
 		},
 		{
-			name: "NotEqual/pass",
+			name: "Equal/fail context func empty",
 			fn: func(tb testing.TB) {
-				test.NotEqual(tb, "apples", "oranges")
+				test.Equal(tb, "apples", "oranges", test.ContextFunc(func() string { return "  " }))
 			},
-			wantFail: false,
+			wantFail: true,
 		},
 		{
-			name: "NotEqual/fail",
+			name: "Equal/fail context func nil",
 			fn: func(tb testing.TB) {
-				test.NotEqual(tb, "apples", "apples")
+				test.Equal(tb, "apples", "oranges", test.ContextFunc(nil))
 			},
 			wantFail: true,
 		},
 		{
-			name: "NotEqual/fail with context",
+			name: "Equal/fail multiple contexts",
 			fn: func(tb testing.TB) {
-				test.NotEqual(tb, 42, 42, test.Context("42 is the meaning of life"))
+				test.Equal(tb, "apples", "oranges", test.Context("eager"), test.ContextFunc(func() string { return "lazy" }))
 			},
 			wantFail: true,
 		},
 		{
-			name: "NotEqual/fail context format",
+			name: "Equal/fail with fields",
 			fn: func(tb testing.TB) {
-				test.NotEqual(tb, 42, 42, test.Context("42 == meaning of life: %v", true))
+				test.Equal(
+					tb,
+					"apples",
+					"oranges",
+					test.Context("comparing fruit"),
+					test.With("case", 3),
+					test.With("input", "foo"),
+					test.With("seed", 42),
+					test.With("case", 4),
+				)
 			},
 			wantFail: true,
 		},
 		{
-			name: "NotEqual/fail with title",
+			name: "Equal/fail with empty field key",
 			fn: func(tb testing.TB) {
-				test.NotEqual(tb, "apples", "apples", test.Title("My fruit test"))
+				test.Equal(tb, "apples", "oranges", test.With(" ", 3))
 			},
 			wantFail: true,
 		},
 		{
-			name: "EqualFunc/pass",
+			name: "Equal/fail with hint",
 			fn: func(tb testing.TB) {
-				test.EqualFunc(tb, []int{1, 2, 3, 4}, []int{1, 2, 3, 4}, slices.Equal)
+				test.Equal(tb, "apples", "oranges", test.Hint("run %s to regenerate the fruit", "go generate ./..."))
 			},
-			wantFail: false,
+			wantFail: true,
 		},
 		{
-			name: "EqualFunc/fail",
+			name: "Equal/fail with empty hint",
 			fn: func(tb testing.TB) {
-				cmp := func(_, _ []string) bool { return false } // Cheating
-				test.EqualFunc(tb, []string{"hello"}, []string{"there"}, cmp)
+				test.Equal(tb, "apples", "oranges", test.Hint(""))
 			},
 			wantFail: true,
 		},
 		{
-			name: "EqualFunc/fail with context",
+			name: "Equal/fail prints identically",
 			fn: func(tb testing.TB) {
-				test.EqualFunc(
-					tb,
-					[]string{"hello"},
-					[]string{"there"},
-					slices.Equal,
-					test.Context("some context here"),
-				)
+				test.Equal[any](tb, 1, int64(1))
 			},
 			wantFail: true,
 		},
 		{
-			name: "EqualFunc/fail context format",
+			name: "Equal/fail string and number",
 			fn: func(tb testing.TB) {
-				test.EqualFunc(
-					tb,
-					[]string{"hello"},
-					[]string{"there"},
-					slices.Equal,
-					test.Context("who's bad at testing... %s", "you"),
-				)
+				test.Equal[any](tb, "1", 1)
 			},
 			wantFail: true,
 		},
 		{
-			name: "EqualFunc/fail with title",
+			name: "Equal/fail time",
 			fn: func(tb testing.TB) {
-				test.EqualFunc(tb, []string{"hello"}, []string{"there"}, slices.Equal, test.Title("Hello!"))
+				start := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+				test.Equal(tb, start, start.Add(time.Second))
 			},
 			wantFail: true,
 		},
 		{
-			name: "NotEqualFunc/pass",
+			name: "Equal/fail nested strings",
 			fn: func(tb testing.TB) {
-				test.NotEqualFunc(tb, []int{1, 2, 3, 4}, []int{5, 6, 7, 8}, slices.Equal)
+				test.Equal(tb, [2]string{"1", ""}, [2]string{"1", " "})
 			},
-			wantFail: false,
+			wantFail: true,
 		},
 		{
-			name: "NotEqualFunc/fail",
+			name: "Equal/fail nested string and number",
 			fn: func(tb testing.TB) {
-				cmp := func(_, _ []string) bool { return true } // Cheating
-				test.NotEqualFunc(tb, []string{"hello"}, []string{"there"}, cmp)
+				test.Equal(tb, [2]any{"1", 1}, [2]any{1, "1"})
 			},
 			wantFail: true,
 		},
 		{
-			name: "NotEqualFunc/fail with context",
+			name: "Equal/fail nested time",
 			fn: func(tb testing.TB) {
-				test.NotEqualFunc(
-					tb,
-					[]string{"hello"},
-					[]string{"hello"},
-					slices.Equal,
-					test.Context("some context here"),
-				)
+				type event struct{ At time.Time }
+
+				start := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+				test.Equal(tb, event{At: start}, event{At: start.Add(time.Second)})
 			},
 			wantFail: true,
 		},
 		{
-			name: "NotEqualFunc/fail context format",
+			name: "Equal/fail nested struct",
 			fn: func(tb testing.TB) {
-				test.NotEqualFunc(
-					tb,
-					[]string{"hello"},
-					[]string{"hello"},
-					slices.Equal,
-					test.Context("who's bad at testing... %s", "you"),
-				)
+				got := person{
+					Name:    "Alice",
+					Email:   "alice@example.com",
+					Address: address{Street: "1 High Street", City: "London", Postcode: "N1 1AA"},
+				}
+				want := got
+				want.Address.City = "Leeds"
+				test.Equal(tb, got, want)
 			},
 			wantFail: true,
 		},
 		{
-			name: "NotEqualFunc/fail with title",
+			name: "Equal/fail go syntax",
 			fn: func(tb testing.TB) {
-				test.NotEqualFunc(tb, []string{"hello"}, []string{"hello"}, slices.Equal, test.Title("Hello!"))
+				test.Equal(tb, address{City: "London"}, address{City: "Leeds"}, test.GoSyntax(true))
 			},
 			wantFail: true,
 		},
 		{
-			name: "NearlyEqual/pass",
+			name: "Equal/fail format with",
 			fn: func(tb testing.TB) {
-				test.NearlyEqual(tb, 3.0000000001, 3.0)
+				test.Equal(tb, address{City: "London"}, address{City: "Leeds"}, test.FormatWith(formatAddress))
 			},
-			wantFail: false,
+			wantFail: true,
 		},
 		{
-			name: "NearlyEqual/fail",
+			name: "Equal/fail format with nested",
 			fn: func(tb testing.TB) {
-				test.NearlyEqual(tb, 3.0000001, 3.0)
+				got := person{Name: "Alice", Address: address{City: "London"}}
+				want := person{Name: "Alice", Address: address{City: "Leeds"}}
+				test.Equal(tb, got, want, test.FormatWith(formatAddress))
 			},
 			wantFail: true,
 		},
 		{
-			name: "NearlyEqual/fail custom tolerance",
+			name: "Equal/fail format with interface",
 			fn: func(tb testing.TB) {
-				test.NearlyEqual(tb, 3.2, 3.0, test.FloatEqualityThreshold(0.1))
+				test.Equal[error](tb, &inputError{msg: "bad"}, &outputError{msg: "bad"}, test.FormatWith(func(err error) string {
+					return fmt.Sprintf("error %T", err)
+				}))
 			},
 			wantFail: true,
 		},
 		{
-			name: "NearlyEqual/fail with context",
+			name: "Equal/fail format with go syntax",
 			fn: func(tb testing.TB) {
-				test.NearlyEqual(tb, 3.0000001, 3.0, test.Context("Numbers don't work that way"))
+				test.Equal(tb, address{City: "London"}, address{City: "Leeds"}, test.GoSyntax(true), test.FormatWith(formatAddress))
 			},
 			wantFail: true,
 		},
 		{
-			name: "NotNearlyEqual/pass",
+			name: "Equal/fail format with nil",
 			fn: func(tb testing.TB) {
-				test.NotNearlyEqual(tb, 3.0000001, 3.0)
+				test.Equal(tb, 1, 2, test.FormatWith[int](nil))
 			},
-			wantFail: false,
+			wantFail: true,
 		},
 		{
-			name: "NotNearlyEqual/fail",
+			name: "Equal/fail go stringer",
 			fn: func(tb testing.TB) {
-				test.NotNearlyEqual(tb, 3.0000000001, 3.0)
+				test.Equal(tb, celsius(20), celsius(21))
 			},
 			wantFail: true,
 		},
 		{
-			name: "NotNearlyEqual/fail custom tolerance",
+			name: "Equal/fail long string",
 			fn: func(tb testing.TB) {
-				test.NotNearlyEqual(tb, 3.05, 3.0, test.FloatEqualityThreshold(0.1))
+				got := strings.Repeat("a", 500) + "b" + strings.Repeat("c", 500)
+				want := strings.Repeat("a", 500) + "x" + strings.Repeat("c", 500)
+				test.Equal(tb, got, want)
 			},
 			wantFail: true,
 		},
 		{
-			name: "NotNearlyEqual/fail with context",
+			name: "Equal/fail long string differs at start",
 			fn: func(tb testing.TB) {
-				test.NotNearlyEqual(tb, 3.0000000001, 3.0, test.Context("Numbers don't work that way"))
+				test.Equal(tb, "x"+strings.Repeat("a", 500), strings.Repeat("a", 501))
 			},
 			wantFail: true,
 		},
 		{
-			name: "Ok/pass",
+			name: "Equal/fail long string prefix",
 			fn: func(tb testing.TB) {
-				test.Ok(tb, nil)
+				test.Equal(tb, strings.Repeat("ab", 100), strings.Repeat("ab", 150))
 			},
-			wantFail: false,
+			wantFail: true,
 		},
 		{
-			name: "Ok/fail",
+			name: "Equal/fail long unicode",
 			fn: func(tb testing.TB) {
-				test.Ok(tb, errors.New("uh oh"))
+				got := strings.Repeat("é", 100) + "1" + strings.Repeat("日本", 100)
+				want := strings.Repeat("é", 100) + "2" + strings.Repeat("日本", 100)
+				test.Equal(tb, got, want)
 			},
 			wantFail: true,
 		},
 		{
-			name: "Ok/fail with context",
+			name: "Equal/fail long difference within rune",
 			fn: func(tb testing.TB) {
-				test.Ok(tb, errors.New("uh oh"), test.Context("Could not frobnicate the baz"))
+				got := strings.Repeat("a", 200) + "é" + strings.Repeat("b", 200)
+				want := strings.Repeat("a", 200) + "è" + strings.Repeat("b", 200)
+				test.Equal(tb, got, want)
 			},
 			wantFail: true,
 		},
 		{
-			name: "Ok/fail with title",
+			name: "Equal/fail long multi-line string",
 			fn: func(tb testing.TB) {
-				test.Ok(tb, errors.New("uh oh"), test.Title("Bang!"))
+				lines := make([]string, 0, 20)
+				for i := range 20 {
+					lines = append(lines, fmt.Sprintf("line %d of the file", i))
+				}
+
+				want := strings.Join(lines, "\n") + "\n"
+				lines[10] = "a changed line"
+				got := strings.Join(lines, "\n") + "\n"
+
+				test.Equal(tb, got, want)
 			},
 			wantFail: true,
 		},
 		{
-			name: "Err/pass",
+			name: "Equal/fail long multi-line string max diff hunks",
 			fn: func(tb testing.TB) {
-				test.Err(tb, errors.New("bang"))
+				lines := make([]string, 0, 20)
+				for i := range 20 {
+					lines = append(lines, fmt.Sprintf("line %d of the file", i))
+				}
+
+				want := strings.Join(lines, "\n") + "\n"
+				lines[2] = "a changed line"
+				lines[16] = "another changed line"
+				got := strings.Join(lines, "\n") + "\n"
+
+				test.Equal(tb, got, want, test.DiffContextLines(1), test.MaxDiffHunks(1))
 			},
-			wantFail: false,
+			wantFail: true,
 		},
 		{
-			name: "Err/fail",
+			name: "Equal/fail long multi-line string not normalized",
 			fn: func(tb testing.TB) {
-				test.Err(tb, nil)
+				lines := make([]string, 0, 20)
+				for i := range 20 {
+					lines = append(lines, fmt.Sprintf("line %d of the file", i))
+				}
+
+				want := strings.Join(lines, "\n") + "\n"
+				got := strings.Join(lines, "\r\n") + "\r\n"
+				crlf := func(data []byte) []byte { return bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n")) }
+
+				test.Equal(tb, got, want, test.Normalize(crlf))
 			},
 			wantFail: true,
 		},
 		{
-			name: "Err/fail with context",
+			name: "Equal/fail multi-line string",
 			fn: func(tb testing.TB) {
-				test.Err(tb, nil, test.Context("Frobnicated the baz when it should have failed"))
+				test.Equal(tb, "one\ntwo\nthree\n", "one\n2\nthree\n", test.Context("parsing numbers"))
 			},
 			wantFail: true,
 		},
 		{
-			name: "Err/fail with title",
+			name: "Equal/fail one multi-line string",
 			fn: func(tb testing.TB) {
-				test.Err(tb, nil, test.Title("Everything is fine?"))
+				test.Equal(tb, "one\ntwo", "one two")
 			},
 			wantFail: true,
 		},
 		{
-			name: "ErrorIs/pass",
+			name: "Equal/fail multi-line named string",
 			fn: func(tb testing.TB) {
-				sentinel := errors.New("sentinel")
-				test.ErrorIs(tb, sentinel, sentinel)
+				type script string
+
+				test.Equal(tb, script("echo hello\nexit 1\n"), script("echo hello\nexit 0\n"))
 			},
-			wantFail: false,
+			wantFail: true,
 		},
 		{
-			name: "ErrorIs/pass wrapped",
+			name: "NotEqual/fail multi-line string",
 			fn: func(tb testing.TB) {
-				sentinel := errors.New("sentinel")
-				wrapped := fmt.Errorf("while frobnicating: %w", sentinel)
-				test.ErrorIs(tb, wrapped, sentinel)
+				test.NotEqual(tb, "one\ntwo\n", "one\ntwo\n")
 			},
-			wantFail: false,
+			wantFail: true,
 		},
 		{
-			name: "ErrorIs/fail",
+			name: "NotEqual/fail long string",
 			fn: func(tb testing.TB) {
-				test.ErrorIs(tb, errors.New("bang"), errors.New("not bang"))
+				test.NotEqual(tb, strings.Repeat("a", 500), strings.Repeat("a", 500))
 			},
 			wantFail: true,
 		},
 		{
-			name: "ErrorIs/fail nil",
+			name: "EqualFunc/fail long bytes",
 			fn: func(tb testing.TB) {
-				test.ErrorIs(tb, nil, errors.New("wanted this one"))
+				got := bytes.Repeat([]byte("a"), 300)
+				want := append(bytes.Repeat([]byte("a"), 200), bytes.Repeat([]byte("b"), 100)...)
+				test.EqualFunc(tb, got, want, bytes.Equal)
 			},
 			wantFail: true,
 		},
 		{
-			name: "ErrorIs/fail with context",
+			name: "EqualFunc/fail bytes",
 			fn: func(tb testing.TB) {
-				test.ErrorIs(tb, errors.New("bang"), errors.New("not bang"), test.Context("Expected the other error"))
+				test.EqualFunc(tb, []byte("hello"), []byte("hello\n"), bytes.Equal)
 			},
 			wantFail: true,
 		},
 		{
-			name: "ErrorIs/fail with title",
+			name: "EqualFunc/fail nested slices",
 			fn: func(tb testing.TB) {
-				test.ErrorIs(tb, errors.New("bang"), errors.New("not bang"), test.Title("Wrong one"))
+				got := []address{{Street: "1 High Street", City: "London"}, {Street: "2 Low Road", City: "Leeds"}}
+				test.EqualFunc(tb, got, nil, slices.Equal)
 			},
 			wantFail: true,
 		},
 		{
-			name: "ErrorAs/pass",
+			name: "Equal/fail with title",
 			fn: func(tb testing.TB) {
-				boom := &inputError{msg: "boom"}
-
-				got := test.ErrorAs[*inputError](tb, boom)
-				if got != boom {
-					tb.Fatal("ErrorAs did not return the matched error")
-				}
+				test.Equal(tb, "apples", "oranges", test.Title("My fruit test"))
 			},
-			wantFail: false,
+			wantFail: true,
 		},
 		{
-			name: "ErrorAs/pass wrapped",
+			name: "NotEqual/pass",
 			fn: func(tb testing.TB) {
-				inner := &inputError{msg: "boom"}
-				wrapped := fmt.Errorf("while frobnicating: %w", inner)
-
-				got := test.ErrorAs[*inputError](tb, wrapped)
-				if got != inner {
-					tb.Fatal("ErrorAs did not return the wrapped error")
-				}
+				test.NotEqual(tb, "apples", "oranges")
 			},
 			wantFail: false,
 		},
 		{
-			name: "ErrorAs/fail",
+			name: "NotEqual/fail",
 			fn: func(tb testing.TB) {
-				test.ErrorAs[*inputError](tb, &outputError{msg: "nope"})
+				test.NotEqual(tb, "apples", "apples")
 			},
 			wantFail: true,
 		},
 		{
-			name: "ErrorAs/fail nil",
+			name: "NotEqual/fail with context",
 			fn: func(tb testing.TB) {
-				test.ErrorAs[*inputError](tb, nil)
+				test.NotEqual(tb, 42, 42, test.Context("42 is the meaning of life"))
 			},
 			wantFail: true,
 		},
 		{
-			name: "ErrorAs/fail with context",
+			name: "NotEqual/fail context format",
 			fn: func(tb testing.TB) {
-				test.ErrorAs[*inputError](tb, &outputError{msg: "nope"}, test.Context("Expected an inputError"))
+				test.NotEqual(tb, 42, 42, test.Context("42 == meaning of life: %v", true))
 			},
 			wantFail: true,
 		},
 		{
-			name: "ErrorAs/fail with title",
+			name: "NotEqual/fail with title",
 			fn: func(tb testing.TB) {
-				test.ErrorAs[*inputError](tb, &outputError{msg: "nope"}, test.Title("Type mismatch"))
+				test.NotEqual(tb, "apples", "apples", test.Title("My fruit test"))
 			},
 			wantFail: true,
 		},
 		{
-			name: "WantErr/pass error",
+			name: "EqualFunc/pass",
 			fn: func(tb testing.TB) {
-				test.WantErr(tb, errors.New("bang"), true) // Wanted an error and got one - should pass
+				test.EqualFunc(tb, []int{1, 2, 3, 4}, []int{1, 2, 3, 4}, slices.Equal)
 			},
 			wantFail: false,
 		},
 		{
-			name: "WantErr/pass nil",
+			name: "EqualFunc/fail",
 			fn: func(tb testing.TB) {
-				test.WantErr(tb, nil, false) // Didn't want an error and got nil - should pass
+				cmp := func(_, _ []string) bool { return false } // Cheating
+				test.EqualFunc(tb, []string{"hello"}, []string{"there"}, cmp)
 			},
-			wantFail: false,
+			wantFail: true,
 		},
 		{
-			name: "WantErr/fail error",
+			name: "EqualFunc/fail with context",
 			fn: func(tb testing.TB) {
-				test.WantErr(tb, errors.New("bang"), false) // Got an error but didn't want one - should fail
+				test.EqualFunc(
+					tb,
+					[]string{"hello"},
+					[]string{"there"},
+					slices.Equal,
+					test.Context("some context here"),
+				)
 			},
 			wantFail: true,
 		},
 		{
-			name: "WantErr/fail nil",
+			name: "EqualFunc/fail context format",
 			fn: func(tb testing.TB) {
-				test.WantErr(tb, nil, true) // Didn't get an error but wanted one - should fail
+				test.EqualFunc(
+					tb,
+					[]string{"hello"},
+					[]string{"there"},
+					slices.Equal,
+					test.Context("who's bad at testing... %s", "you"),
+				)
 			},
 			wantFail: true,
 		},
 		{
-			name: "WantErr/fail with context",
+			name: "EqualFunc/fail with title",
 			fn: func(tb testing.TB) {
-				test.WantErr(tb, errors.New("bang"), false, test.Context("Errors are bad!"))
+				test.EqualFunc(tb, []string{"hello"}, []string{"there"}, slices.Equal, test.Title("Hello!"))
 			},
 			wantFail: true,
 		},
 		{
-			name: "WantErr/fail with title",
+			name: "EqualFunc/fail with hint",
 			fn: func(tb testing.TB) {
-				test.WantErr(tb, errors.New("bang"), false, test.Title("A very bad test"))
+				test.EqualFunc(tb, []int{1}, []int{2}, slices.Equal, test.Hint("check the fixtures"), test.Hint("or don't"))
 			},
 			wantFail: true,
 		},
 		{
-			name: "True/pass",
+			name: "NotEqualFunc/pass",
 			fn: func(tb testing.TB) {
-				test.True(tb, true)
+				test.NotEqualFunc(tb, []int{1, 2, 3, 4}, []int{5, 6, 7, 8}, slices.Equal)
 			},
 			wantFail: false,
 		},
 		{
-			name: "True/fail",
+			name: "NotEqualFunc/fail",
 			fn: func(tb testing.TB) {
-				test.True(tb, false)
+				cmp := func(_, _ []string) bool { return true } // Cheating
+				test.NotEqualFunc(tb, []string{"hello"}, []string{"there"}, cmp)
 			},
 			wantFail: true,
 		},
 		{
-			name: "True/fail with context",
+			name: "NotEqualFunc/fail with context",
 			fn: func(tb testing.TB) {
-				test.True(tb, false, test.Context("must always be true"))
+				test.NotEqualFunc(
+					tb,
+					[]string{"hello"},
+					[]string{"hello"},
+					slices.Equal,
+					test.Context("some context here"),
+				)
 			},
 			wantFail: true,
 		},
 		{
-			name: "True/fail with title",
+			name: "NotEqualFunc/fail context format",
 			fn: func(tb testing.TB) {
-				test.True(tb, false, test.Title("Argh!"))
+				test.NotEqualFunc(
+					tb,
+					[]string{"hello"},
+					[]string{"hello"},
+					slices.Equal,
+					test.Context("who's bad at testing... %s", "you"),
+				)
 			},
 			wantFail: true,
 		},
 		{
-			name: "False/pass",
+			name: "NotEqualFunc/fail with title",
 			fn: func(tb testing.TB) {
-				test.False(tb, false)
+				test.NotEqualFunc(tb, []string{"hello"}, []string{"hello"}, slices.Equal, test.Title("Hello!"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "NearlyEqual/pass",
+			fn: func(tb testing.TB) {
+				test.NearlyEqual(tb, 3.0000000001, 3.0)
+			},
+			wantFail: false,
+		},
+		{
+			name: "NearlyEqual/fail",
+			fn: func(tb testing.TB) {
+				test.NearlyEqual(tb, 3.0000001, 3.0)
+			},
+			wantFail: true,
+		},
+		{
+			name: "NearlyEqual/fail custom tolerance",
+			fn: func(tb testing.TB) {
+				test.NearlyEqual(tb, 3.2, 3.0, test.FloatEqualityThreshold(0.1))
+			},
+			wantFail: true,
+		},
+		{
+			name: "NearlyEqual/fail with context",
+			fn: func(tb testing.TB) {
+				test.NearlyEqual(tb, 3.0000001, 3.0, test.Context("Numbers don't work that way"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "NearlyEqual/pass relative tolerance",
+			fn: func(tb testing.TB) {
+				test.NearlyEqual(tb, 1.0000000001e12, 1e12, test.RelativeTolerance(1e-9))
+			},
+			wantFail: false,
+		},
+		{
+			name: "NearlyEqual/fail relative tolerance",
+			fn: func(tb testing.TB) {
+				test.NearlyEqual(tb, 1.1e-12, 1e-12, test.RelativeTolerance(1e-3))
+			},
+			wantFail: true,
+		},
+		{
+			name: "NearlyEqual/pass ulp tolerance",
+			fn: func(tb testing.TB) {
+				a, b := 0.1, 0.2
+				test.NearlyEqual(tb, a+b, 0.3, test.ULPTolerance(1))
+			},
+			wantFail: false,
+		},
+		{
+			name: "NearlyEqual/fail ulp tolerance",
+			fn: func(tb testing.TB) {
+				test.NearlyEqual(tb, math.Nextafter(math.Nextafter(1, 2), 2), 1.0, test.ULPTolerance(1))
+			},
+			wantFail: true,
+		},
+		{
+			name: "NearlyEqual/fail ulp tolerance float32",
+			fn: func(tb testing.TB) {
+				test.NearlyEqual(tb, float32(1.0000002), float32(1), test.ULPTolerance(1))
+			},
+			wantFail: true,
+		},
+		{
+			name: "NearlyEqual/pass ulp tolerance signed zeros",
+			fn: func(tb testing.TB) {
+				test.NearlyEqual(tb, math.Copysign(0, -1), 0.0, test.ULPTolerance(0))
+			},
+			wantFail: false,
+		},
+		{
+			name: "NearlyEqual/pass ulp tolerance negative to zero",
+			fn: func(tb testing.TB) {
+				test.NearlyEqual(tb, -math.SmallestNonzeroFloat64, 0.0, test.ULPTolerance(1))
+			},
+			wantFail: false,
+		},
+		{
+			name: "NearlyEqual/pass ulp tolerance positive to zero",
+			fn: func(tb testing.TB) {
+				test.NearlyEqual(tb, math.SmallestNonzeroFloat64, math.Copysign(0, -1), test.ULPTolerance(1))
+			},
+			wantFail: false,
+		},
+		{
+			name: "NearlyEqual/pass ulp tolerance across zero",
+			fn: func(tb testing.TB) {
+				test.NearlyEqual(tb, -math.SmallestNonzeroFloat64, math.SmallestNonzeroFloat64, test.ULPTolerance(2))
+			},
+			wantFail: false,
+		},
+		{
+			name: "NearlyEqual/fail ulp tolerance across zero",
+			fn: func(tb testing.TB) {
+				test.NearlyEqual(tb, -math.SmallestNonzeroFloat64, math.SmallestNonzeroFloat64, test.ULPTolerance(1))
+			},
+			wantFail: true,
+		},
+		{
+			name: "NearlyEqual/pass ulp tolerance float32 across zero",
+			fn: func(tb testing.TB) {
+				tiny := math.Float32frombits(1)
+				test.NearlyEqual(tb, -tiny, tiny, test.ULPTolerance(2))
+			},
+			wantFail: false,
+		},
+		{
+			name: "NearlyEqual/pass abs or rel tolerance near zero",
+			fn: func(tb testing.TB) {
+				test.NearlyEqual(tb, 1e-15, 0.0, test.AbsOrRelTolerance(1e-12, 1e-9))
+			},
+			wantFail: false,
+		},
+		{
+			name: "NearlyEqual/pass abs or rel tolerance large",
+			fn: func(tb testing.TB) {
+				test.NearlyEqual(tb, 1.0000000001e15, 1e15, test.AbsOrRelTolerance(1e-12, 1e-9))
+			},
+			wantFail: false,
+		},
+		{
+			name: "NearlyEqual/fail abs or rel tolerance",
+			fn: func(tb testing.TB) {
+				test.NearlyEqual(tb, 1.001, 1.0, test.AbsOrRelTolerance(1e-12, 1e-9))
+			},
+			wantFail: true,
+		},
+		{
+			name: "NearlyEqual/fail relative tolerance infinity",
+			fn: func(tb testing.TB) {
+				test.NearlyEqual(tb, math.Inf(1), math.MaxFloat64, test.RelativeTolerance(0.5))
+			},
+			wantFail: true,
+		},
+		{
+			name: "NearlyEqual/pass infinity",
+			fn: func(tb testing.TB) {
+				test.NearlyEqual(tb, math.Inf(1), math.Inf(1))
+			},
+			wantFail: false,
+		},
+		{
+			name: "NearlyEqual/pass negative infinity",
+			fn: func(tb testing.TB) {
+				test.NearlyEqual(tb, math.Inf(-1), math.Inf(-1), test.ULPTolerance(0))
+			},
+			wantFail: false,
+		},
+		{
+			name: "NearlyEqual/pass signed zeros",
+			fn: func(tb testing.TB) {
+				test.NearlyEqual(tb, math.Copysign(0, -1), 0.0, test.FloatEqualityThreshold(0))
+			},
+			wantFail: false,
+		},
+		{
+			name: "NearlyEqual/pass NaN equal",
+			fn: func(tb testing.TB) {
+				test.NearlyEqual(tb, math.NaN(), math.NaN(), test.NaNEqual(true))
+			},
+			wantFail: false,
+		},
+		{
+			name: "NearlyEqual/fail NaN",
+			fn: func(tb testing.TB) {
+				test.NearlyEqual(tb, math.NaN(), math.NaN())
+			},
+			wantFail: true,
+		},
+		{
+			name: "NearlyEqual/fail got NaN",
+			fn: func(tb testing.TB) {
+				test.NearlyEqual(tb, math.NaN(), 3.0, test.NaNEqual(true))
+			},
+			wantFail: true,
+		},
+		{
+			name: "NearlyEqual/fail want NaN",
+			fn: func(tb testing.TB) {
+				test.NearlyEqual(tb, 3.0, math.NaN())
+			},
+			wantFail: true,
+		},
+		{
+			name: "NearlyEqual/fail opposite infinities",
+			fn: func(tb testing.TB) {
+				test.NearlyEqual(tb, math.Inf(1), math.Inf(-1))
+			},
+			wantFail: true,
+		},
+		{
+			name: "NearlyEqual/fail got infinity",
+			fn: func(tb testing.TB) {
+				test.NearlyEqual(tb, math.Inf(-1), 3.0)
+			},
+			wantFail: true,
+		},
+		{
+			name: "NearlyEqual/fail want infinity",
+			fn: func(tb testing.TB) {
+				test.NearlyEqual(tb, float32(3), float32(math.Inf(1)), test.RelativeTolerance(1))
+			},
+			wantFail: true,
+		},
+		{
+			name: "NotNearlyEqual/pass",
+			fn: func(tb testing.TB) {
+				test.NotNearlyEqual(tb, 3.0000001, 3.0)
+			},
+			wantFail: false,
+		},
+		{
+			name: "NotNearlyEqual/fail",
+			fn: func(tb testing.TB) {
+				test.NotNearlyEqual(tb, 3.0000000001, 3.0)
+			},
+			wantFail: true,
+		},
+		{
+			name: "NotNearlyEqual/fail custom tolerance",
+			fn: func(tb testing.TB) {
+				test.NotNearlyEqual(tb, 3.05, 3.0, test.FloatEqualityThreshold(0.1))
+			},
+			wantFail: true,
+		},
+		{
+			name: "NotNearlyEqual/fail with context",
+			fn: func(tb testing.TB) {
+				test.NotNearlyEqual(tb, 3.0000000001, 3.0, test.Context("Numbers don't work that way"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "NotNearlyEqual/fail relative tolerance",
+			fn: func(tb testing.TB) {
+				test.NotNearlyEqual(tb, 1.0000000001e12, 1e12, test.RelativeTolerance(1e-9))
+			},
+			wantFail: true,
+		},
+		{
+			name: "NotNearlyEqual/fail ulp tolerance",
+			fn: func(tb testing.TB) {
+				test.NotNearlyEqual(tb, math.Nextafter(1, 2), 1.0, test.ULPTolerance(1))
+			},
+			wantFail: true,
+		},
+		{
+			name: "NotNearlyEqual/fail abs or rel tolerance",
+			fn: func(tb testing.TB) {
+				test.NotNearlyEqual(tb, 1e-15, 0.0, test.AbsOrRelTolerance(1e-12, 1e-9))
+			},
+			wantFail: true,
+		},
+		{
+			name: "NotNearlyEqual/pass NaN",
+			fn: func(tb testing.TB) {
+				test.NotNearlyEqual(tb, math.NaN(), math.NaN())
+			},
+			wantFail: false,
+		},
+		{
+			name: "NotNearlyEqual/pass opposite infinities",
+			fn: func(tb testing.TB) {
+				test.NotNearlyEqual(tb, math.Inf(1), math.Inf(-1))
+			},
+			wantFail: false,
+		},
+		{
+			name: "NotNearlyEqual/fail NaN equal",
+			fn: func(tb testing.TB) {
+				test.NotNearlyEqual(tb, math.NaN(), math.NaN(), test.NaNEqual(true))
+			},
+			wantFail: true,
+		},
+		{
+			name: "NotNearlyEqual/fail infinity",
+			fn: func(tb testing.TB) {
+				test.NotNearlyEqual(tb, math.Inf(1), math.Inf(1))
+			},
+			wantFail: true,
+		},
+		{
+			name: "NearlyEqualSlice/pass",
+			fn: func(tb testing.TB) {
+				test.NearlyEqualSlice(tb, []float64{1.0, 2.0000000001, 3.0}, []float64{1.0, 2.0, 3.0})
+			},
+			wantFail: false,
+		},
+		{
+			name: "NearlyEqualSlice/pass empty",
+			fn: func(tb testing.TB) {
+				test.NearlyEqualSlice(tb, nil, []float32{})
+			},
+			wantFail: false,
+		},
+		{
+			name: "NearlyEqualSlice/fail",
+			fn: func(tb testing.TB) {
+				got := []float64{1.0, 2.5, 3.0, 4.0, 5.25}
+				want := []float64{1.0, 2.0, 3.0, 4.0000000001, 5.0}
+				test.NearlyEqualSlice(tb, got, want)
+			},
+			wantFail: true,
+		},
+		{
+			name: "NearlyEqualSlice/fail special values",
+			fn: func(tb testing.TB) {
+				got := []float64{math.NaN(), math.Inf(1), 3.0}
+				want := []float64{1.0, 2.0, 3.5}
+				test.NearlyEqualSlice(tb, got, want)
+			},
+			wantFail: true,
+		},
+		{
+			name: "NearlyEqualSlice/fail length",
+			fn: func(tb testing.TB) {
+				test.NearlyEqualSlice(tb, []float64{1.0, 2.0}, []float64{1.0, 2.0, 3.0})
+			},
+			wantFail: true,
+		},
+		{
+			name: "NearlyEqualSlice/fail with context",
+			fn: func(tb testing.TB) {
+				got := []float32{0.1, 0.2}
+				want := []float32{0.1, 0.3}
+				test.NearlyEqualSlice(tb, got, want, test.RelativeTolerance(0.01), test.Context("weights drifted"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "NearlyEqualSlice/fail long",
+			fn: func(tb testing.TB) {
+				got := make([]float64, 100)
+				want := make([]float64, 100)
+				want[80] = 1
+				test.NearlyEqualSlice(tb, got, want)
+			},
+			wantFail: true,
+		},
+		{
+			name: "NearlyEqualSlice/fail go syntax",
+			fn: func(tb testing.TB) {
+				test.NearlyEqualSlice(tb, []float64{1, 2}, []float64{1, 3}, test.GoSyntax(true))
+			},
+			wantFail: true,
+		},
+		{
+			name: "NearlyEqualSlice/fail format with",
+			fn: func(tb testing.TB) {
+				test.NearlyEqualSlice(tb, []float64{1, 2}, []float64{1, 3}, test.FormatWith(func(f float64) string {
+					return fmt.Sprintf("%.2f", f)
+				}))
+			},
+			wantFail: true,
+		},
+		{
+			name: "NearlyEqualMatrix/pass",
+			fn: func(tb testing.TB) {
+				got := [][]float64{{1, 0.0000000001}, {0, 1}}
+				want := [][]float64{{1, 0}, {0, 1}}
+				test.NearlyEqualMatrix(tb, got, want)
+			},
+			wantFail: false,
+		},
+		{
+			name: "NearlyEqualMatrix/fail",
+			fn: func(tb testing.TB) {
+				got := [][]float64{{1, 0.5, 0}, {0, 1, 0}, {0, 0, 0.75}}
+				want := [][]float64{{1, 0, 0}, {0, 1, 0}, {0, 0, 1}}
+				test.NearlyEqualMatrix(tb, got, want)
+			},
+			wantFail: true,
+		},
+		{
+			name: "NearlyEqualMatrix/fail rows",
+			fn: func(tb testing.TB) {
+				test.NearlyEqualMatrix(tb, [][]float64{{1, 0}}, [][]float64{{1, 0}, {0, 1}})
+			},
+			wantFail: true,
+		},
+		{
+			name: "NearlyEqualMatrix/fail row length",
+			fn: func(tb testing.TB) {
+				test.NearlyEqualMatrix(tb, [][]float64{{1, 0}, {0}}, [][]float64{{1, 0}, {0, 1}})
+			},
+			wantFail: true,
+		},
+		{
+			name: "NearlyEqualComplex/pass",
+			fn: func(tb testing.TB) {
+				test.NearlyEqualComplex(tb, cmplx.Exp(complex(0, math.Pi)), -1)
+			},
+			wantFail: false,
+		},
+		{
+			name: "NearlyEqualComplex/fail imaginary",
+			fn: func(tb testing.TB) {
+				test.NearlyEqualComplex(tb, complex(1, 2), complex(1, 2.1))
+			},
+			wantFail: true,
+		},
+		{
+			name: "NearlyEqualComplex/fail both",
+			fn: func(tb testing.TB) {
+				test.NearlyEqualComplex(tb, complex64(complex(1.5, 2)), complex64(complex(1, 2.1)), test.ULPTolerance(2))
+			},
+			wantFail: true,
+		},
+		{
+			name: "Less/pass",
+			fn: func(tb testing.TB) {
+				test.Less(tb, 1, 2)
+			},
+			wantFail: false,
+		},
+		{
+			name: "Less/fail",
+			fn: func(tb testing.TB) {
+				test.Less(tb, 2, 1)
+			},
+			wantFail: true,
+		},
+		{
+			name: "Less/fail equal",
+			fn: func(tb testing.TB) {
+				test.Less(tb, "apples", "apples")
+			},
+			wantFail: true,
+		},
+		{
+			name: "Less/fail with context",
+			fn: func(tb testing.TB) {
+				test.Less(tb, 250*time.Millisecond, 100*time.Millisecond, test.Context("request was too slow"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "LessOrEqual/pass",
+			fn: func(tb testing.TB) {
+				test.LessOrEqual(tb, 2, 2)
+			},
+			wantFail: false,
+		},
+		{
+			name: "LessOrEqual/fail",
+			fn: func(tb testing.TB) {
+				test.LessOrEqual(tb, 3.5, 2.0)
+			},
+			wantFail: true,
+		},
+		{
+			name: "Greater/pass",
+			fn: func(tb testing.TB) {
+				test.Greater(tb, 2, 1)
+			},
+			wantFail: false,
+		},
+		{
+			name: "Greater/fail",
+			fn: func(tb testing.TB) {
+				test.Greater(tb, 0, 0, test.Title("No results"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "GreaterOrEqual/pass",
+			fn: func(tb testing.TB) {
+				test.GreaterOrEqual(tb, "b", "a")
+			},
+			wantFail: false,
+		},
+		{
+			name: "GreaterOrEqual/fail",
+			fn: func(tb testing.TB) {
+				test.GreaterOrEqual(tb, "a", "b")
+			},
+			wantFail: true,
+		},
+		{
+			name: "Between/pass",
+			fn: func(tb testing.TB) {
+				test.Between(tb, 10, 1, 10)
+			},
+			wantFail: false,
+		},
+		{
+			name: "Between/fail below",
+			fn: func(tb testing.TB) {
+				test.Between(tb, 0, 1, 10)
+			},
+			wantFail: true,
+		},
+		{
+			name: "Between/fail above",
+			fn: func(tb testing.TB) {
+				test.Between(tb, 11, 1, 10, test.Context("dice only have 10 sides"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "Between/fail inverted range",
+			fn: func(tb testing.TB) {
+				test.Between(tb, 5, 10, 1)
+			},
+			wantFail: true,
+		},
+		{
+			name: "LessFunc/pass",
+			fn: func(tb testing.TB) {
+				test.LessFunc(tb, []int{1, 2}, []int{1, 3}, slices.Compare)
+			},
+			wantFail: false,
+		},
+		{
+			name: "LessFunc/fail",
+			fn: func(tb testing.TB) {
+				test.LessFunc(tb, []int{1, 3}, []int{1, 2}, slices.Compare)
+			},
+			wantFail: true,
+		},
+		{
+			name: "LessOrEqualFunc/fail",
+			fn: func(tb testing.TB) {
+				start := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+				test.LessOrEqualFunc(tb, start.Add(time.Hour), start, time.Time.Compare)
+			},
+			wantFail: true,
+		},
+		{
+			name: "GreaterFunc/fail",
+			fn: func(tb testing.TB) {
+				test.GreaterFunc(tb, []string{"a"}, []string{"a"}, slices.Compare)
+			},
+			wantFail: true,
+		},
+		{
+			name: "GreaterOrEqualFunc/pass",
+			fn: func(tb testing.TB) {
+				test.GreaterOrEqualFunc(tb, []string{"b"}, []string{"a"}, slices.Compare)
+			},
+			wantFail: false,
+		},
+		{
+			name: "BetweenFunc/pass",
+			fn: func(tb testing.TB) {
+				test.BetweenFunc(tb, []int{2}, []int{1}, []int{3}, slices.Compare)
+			},
+			wantFail: false,
+		},
+		{
+			name: "BetweenFunc/fail",
+			fn: func(tb testing.TB) {
+				test.BetweenFunc(tb, []int{4}, []int{1}, []int{3}, slices.Compare)
+			},
+			wantFail: true,
+		},
+		{
+			name: "BetweenFunc/fail inverted range",
+			fn: func(tb testing.TB) {
+				test.BetweenFunc(tb, []int{2}, []int{3}, []int{1}, slices.Compare)
+			},
+			wantFail: true,
+		},
+		{
+			name: "Sorted/pass",
+			fn: func(tb testing.TB) {
+				test.Sorted(tb, []int{1, 2, 2, 3, 10})
+			},
+			wantFail: false,
+		},
+		{
+			name: "Sorted/pass empty",
+			fn: func(tb testing.TB) {
+				test.Sorted(tb, []string{})
+			},
+			wantFail: false,
+		},
+		{
+			name: "Sorted/fail",
+			fn: func(tb testing.TB) {
+				test.Sorted(tb, []int{1, 2, 3, 4, 5, 6, 8, 7, 9, 10, 11, 12, 13, 14})
+			},
+			wantFail: true,
+		},
+		{
+			name: "Sorted/fail start",
+			fn: func(tb testing.TB) {
+				test.Sorted(tb, []string{"banana", "apple", "cherry"})
+			},
+			wantFail: true,
+		},
+		{
+			name: "Sorted/fail with context",
+			fn: func(tb testing.TB) {
+				test.Sorted(tb, []int{3, 2, 1}, test.Context("results should be ordered by score"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "StrictlySorted/pass",
+			fn: func(tb testing.TB) {
+				test.StrictlySorted(tb, []int{1, 2, 3})
+			},
+			wantFail: false,
+		},
+		{
+			name: "StrictlySorted/fail",
+			fn: func(tb testing.TB) {
+				test.StrictlySorted(tb, []int{1, 2, 2, 3})
+			},
+			wantFail: true,
+		},
+		{
+			name: "SortedFunc/pass",
+			fn: func(tb testing.TB) {
+				test.SortedFunc(tb, []string{"a", "B", "c"}, compareFold)
+			},
+			wantFail: false,
+		},
+		{
+			name: "SortedFunc/fail",
+			fn: func(tb testing.TB) {
+				test.SortedFunc(tb, []string{"a", "C", "b"}, compareFold)
+			},
+			wantFail: true,
+		},
+		{
+			name: "StrictlySortedFunc/fail",
+			fn: func(tb testing.TB) {
+				test.StrictlySortedFunc(tb, []string{"a", "b", "B"}, compareFold)
+			},
+			wantFail: true,
+		},
+		{
+			name: "Nil/pass",
+			fn: func(tb testing.TB) {
+				var m map[string]int
+				test.Nil(tb, m)
+			},
+			wantFail: false,
+		},
+		{
+			name: "Nil/pass nil interface",
+			fn: func(tb testing.TB) {
+				var err error
+				test.Nil(tb, err)
+			},
+			wantFail: false,
+		},
+		{
+			name: "Nil/fail",
+			fn: func(tb testing.TB) {
+				test.Nil(tb, []int{1, 2, 3})
+			},
+			wantFail: true,
+		},
+		{
+			name: "Nil/fail typed nil",
+			fn: func(tb testing.TB) {
+				test.Nil(tb, typedNilError())
+			},
+			wantFail: true,
+		},
+		{
+			name: "Nil/fail never nil",
+			fn: func(tb testing.TB) {
+				test.Nil(tb, 42)
+			},
+			wantFail: true,
+		},
+		{
+			name: "NotNil/pass",
+			fn: func(tb testing.TB) {
+				test.NotNil(tb, &inputError{msg: "bang"})
+			},
+			wantFail: false,
+		},
+		{
+			name: "NotNil/pass never nil",
+			fn: func(tb testing.TB) {
+				test.NotNil(tb, "hello")
+			},
+			wantFail: false,
+		},
+		{
+			name: "NotNil/fail",
+			fn: func(tb testing.TB) {
+				var fn func()
+				test.NotNil(tb, fn)
+			},
+			wantFail: true,
+		},
+		{
+			name: "NotNil/fail nil interface",
+			fn: func(tb testing.TB) {
+				var err error
+				test.NotNil(tb, err, test.Context("should have errored"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "NotNil/fail typed nil",
+			fn: func(tb testing.TB) {
+				test.NotNil(tb, typedNilError())
+			},
+			wantFail: true,
+		},
+		{
+			name: "Zero/pass",
+			fn: func(tb testing.TB) {
+				test.Zero(tb, struct{ a, b int }{})
+			},
+			wantFail: false,
+		},
+		{
+			name: "Zero/fail",
+			fn: func(tb testing.TB) {
+				test.Zero(tb, struct{ a, b int }{a: 1})
+			},
+			wantFail: true,
+		},
+		{
+			name: "Zero/fail typed nil",
+			fn: func(tb testing.TB) {
+				test.Zero(tb, typedNilError())
+			},
+			wantFail: true,
+		},
+		{
+			name: "NotZero/pass",
+			fn: func(tb testing.TB) {
+				test.NotZero(tb, 42)
+			},
+			wantFail: false,
+		},
+		{
+			name: "NotZero/fail",
+			fn: func(tb testing.TB) {
+				test.NotZero(tb, "")
+			},
+			wantFail: true,
+		},
+		{
+			name: "HasPrefix/pass",
+			fn: func(tb testing.TB) {
+				test.HasPrefix(tb, "usage: demo [flags]", "usage:")
+			},
+			wantFail: false,
+		},
+		{
+			name: "HasPrefix/fail",
+			fn: func(tb testing.TB) {
+				test.HasPrefix(tb, "Usage: demo [flags]", "usage:")
+			},
+			wantFail: true,
+		},
+		{
+			name: "HasPrefix/fail short",
+			fn: func(tb testing.TB) {
+				test.HasPrefix(tb, "use", "usage:", test.Context("printing help"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "HasPrefix/fail long",
+			fn: func(tb testing.TB) {
+				got := strings.Repeat("a", 300) + "b" + strings.Repeat("c", 300)
+				test.HasPrefix(tb, got, strings.Repeat("a", 300)+"x")
+			},
+			wantFail: true,
+		},
+		{
+			name: "HasPrefix/fail long escaped",
+			fn: func(tb testing.TB) {
+				tabs := strings.Repeat("\t", 200)
+				test.HasPrefix(tb, tabs+"abc", tabs+"abX")
+			},
+			wantFail: true,
+		},
+		{
+			name: "HasSuffix/pass",
+			fn: func(tb testing.TB) {
+				test.HasSuffix(tb, "report.json", ".json")
+			},
+			wantFail: false,
+		},
+		{
+			name: "HasSuffix/fail",
+			fn: func(tb testing.TB) {
+				test.HasSuffix(tb, "report.yaml\n", ".yaml")
+			},
+			wantFail: true,
+		},
+		{
+			name: "HasSuffix/fail long",
+			fn: func(tb testing.TB) {
+				test.HasSuffix(tb, strings.Repeat("a", 500)+".yaml", ".json")
+			},
+			wantFail: true,
+		},
+		{
+			name: "ContainsSubstring/pass",
+			fn: func(tb testing.TB) {
+				test.ContainsSubstring(tb, "usage: demo [flags]", "demo")
+			},
+			wantFail: false,
+		},
+		{
+			name: "ContainsSubstring/fail",
+			fn: func(tb testing.TB) {
+				test.ContainsSubstring(tb, "usage: demo [flags]", "--help")
+			},
+			wantFail: true,
+		},
+		{
+			name: "ContainsSubstring/fail long",
+			fn: func(tb testing.TB) {
+				test.ContainsSubstring(tb, strings.Repeat("x", 10000), "usage")
+			},
+			wantFail: true,
+		},
+		{
+			name: "Matches/pass",
+			fn: func(tb testing.TB) {
+				test.Matches(tb, "v1.2.3", `^v\d+\.\d+\.\d+$`)
+			},
+			wantFail: false,
+		},
+		{
+			name: "Matches/fail",
+			fn: func(tb testing.TB) {
+				test.Matches(tb, "hello", `^\d+$`)
+			},
+			wantFail: true,
+		},
+		{
+			name: "Matches/fail near miss",
+			fn: func(tb testing.TB) {
+				test.Matches(tb, "v1.2", `^v\d+\.\d+\.\d+$`)
+			},
+			wantFail: true,
+		},
+		{
+			name: "Matches/fail near miss literal",
+			fn: func(tb testing.TB) {
+				test.Matches(tb, "usage: demo [options]", `usage: \w+ \[flags\]`)
+			},
+			wantFail: true,
+		},
+		{
+			name: "Matches/invalid pattern",
+			fn: func(tb testing.TB) {
+				test.Matches(tb, "hello", `(unclosed`)
+			},
+			wantFail: true,
+		},
+		{
+			name: "ErrorContains/pass",
+			fn: func(tb testing.TB) {
+				test.ErrorContains(tb, errors.New("open config.toml: permission denied"), "permission denied")
+			},
+			wantFail: false,
+		},
+		{
+			name: "ErrorContains/fail",
+			fn: func(tb testing.TB) {
+				err := fmt.Errorf("load: %w", errors.New("open config.toml: no such file or directory"))
+				test.ErrorContains(tb, err, "permission denied")
+			},
+			wantFail: true,
+		},
+		{
+			name: "ErrorContains/fail long",
+			fn: func(tb testing.TB) {
+				test.ErrorContains(tb, errors.New(strings.Repeat("bad input ", 100)), "timeout")
+			},
+			wantFail: true,
+		},
+		{
+			name: "ErrorContains/fail nil",
+			fn: func(tb testing.TB) {
+				test.ErrorContains(tb, nil, "permission denied")
+			},
+			wantFail: true,
+		},
+		{
+			name: "ErrorMatches/pass",
+			fn: func(tb testing.TB) {
+				test.ErrorMatches(tb, errors.New("open config.toml: permission denied"), `^open \S+: permission denied$`)
+			},
+			wantFail: false,
+		},
+		{
+			name: "ErrorMatches/fail",
+			fn: func(tb testing.TB) {
+				err := errors.New("open config.toml: no such file or directory")
+				test.ErrorMatches(tb, err, `^open \S+: permission denied$`)
+			},
+			wantFail: true,
+		},
+		{
+			name: "ErrorMatches/fail nil",
+			fn: func(tb testing.TB) {
+				test.ErrorMatches(tb, nil, `^open \S+: permission denied$`, test.Context("reading the config"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "ErrorMatches/invalid pattern",
+			fn: func(tb testing.TB) {
+				test.ErrorMatches(tb, errors.New("bang"), `[a-`)
+			},
+			wantFail: true,
+		},
+		{
+			name: "Ok/pass",
+			fn: func(tb testing.TB) {
+				test.Ok(tb, nil)
+			},
+			wantFail: false,
+		},
+		{
+			name: "Ok/fail",
+			fn: func(tb testing.TB) {
+				test.Ok(tb, errors.New("uh oh"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "Ok/fail wrapped",
+			fn: func(tb testing.TB) {
+				test.Ok(tb, fmt.Errorf("save: %w", errors.Join(errors.New("first"), errors.New("second"))))
+			},
+			wantFail: true,
+		},
+		{
+			name: "Ok/fail with context",
+			fn: func(tb testing.TB) {
+				test.Ok(tb, errors.New("uh oh"), test.Context("Could not frobnicate the baz"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "Ok/fail with title",
+			fn: func(tb testing.TB) {
+				test.Ok(tb, errors.New("uh oh"), test.Title("Bang!"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "Err/pass",
+			fn: func(tb testing.TB) {
+				test.Err(tb, errors.New("bang"))
+			},
+			wantFail: false,
+		},
+		{
+			name: "Err/fail",
+			fn: func(tb testing.TB) {
+				test.Err(tb, nil)
+			},
+			wantFail: true,
+		},
+		{
+			name: "Err/fail with context",
+			fn: func(tb testing.TB) {
+				test.Err(tb, nil, test.Context("Frobnicated the baz when it should have failed"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "Err/fail with title",
+			fn: func(tb testing.TB) {
+				test.Err(tb, nil, test.Title("Everything is fine?"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "ErrorIs/pass",
+			fn: func(tb testing.TB) {
+				sentinel := errors.New("sentinel")
+				test.ErrorIs(tb, sentinel, sentinel)
+			},
+			wantFail: false,
+		},
+		{
+			name: "ErrorIs/pass wrapped",
+			fn: func(tb testing.TB) {
+				sentinel := errors.New("sentinel")
+				wrapped := fmt.Errorf("while frobnicating: %w", sentinel)
+				test.ErrorIs(tb, wrapped, sentinel)
+			},
+			wantFail: false,
+		},
+		{
+			name: "ErrorIs/fail",
+			fn: func(tb testing.TB) {
+				test.ErrorIs(tb, errors.New("bang"), errors.New("not bang"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "ErrorIs/fail wrapped",
+			fn: func(tb testing.TB) {
+				inner := &inputError{msg: "bad input"}
+				err := fmt.Errorf("load config: %w", errors.Join(inner, fmt.Errorf("close: %w", errors.New("disk full"))))
+				test.ErrorIs(tb, err, errors.New("not there"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "ErrorIs/fail nil",
+			fn: func(tb testing.TB) {
+				test.ErrorIs(tb, nil, errors.New("wanted this one"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "ErrorIs/fail with context",
+			fn: func(tb testing.TB) {
+				test.ErrorIs(tb, errors.New("bang"), errors.New("not bang"), test.Context("Expected the other error"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "ErrorIs/fail with title",
+			fn: func(tb testing.TB) {
+				test.ErrorIs(tb, errors.New("bang"), errors.New("not bang"), test.Title("Wrong one"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "ErrorAs/pass",
+			fn: func(tb testing.TB) {
+				boom := &inputError{msg: "boom"}
+
+				got := test.ErrorAs[*inputError](tb, boom)
+				if got != boom {
+					tb.Fatal("ErrorAs did not return the matched error")
+				}
+			},
+			wantFail: false,
+		},
+		{
+			name: "ErrorAs/pass wrapped",
+			fn: func(tb testing.TB) {
+				inner := &inputError{msg: "boom"}
+				wrapped := fmt.Errorf("while frobnicating: %w", inner)
+
+				got := test.ErrorAs[*inputError](tb, wrapped)
+				if got != inner {
+					tb.Fatal("ErrorAs did not return the wrapped error")
+				}
+			},
+			wantFail: false,
+		},
+		{
+			name: "ErrorAs/fail",
+			fn: func(tb testing.TB) {
+				test.ErrorAs[*inputError](tb, &outputError{msg: "nope"})
+			},
+			wantFail: true,
+		},
+		{
+			name: "ErrorAs/fail wrapped",
+			fn: func(tb testing.TB) {
+				err := fmt.Errorf("while frobnicating: %w", &outputError{msg: "nope"})
+				test.ErrorAs[*inputError](tb, err)
+			},
+			wantFail: true,
+		},
+		{
+			name: "ErrorAs/fail nil",
+			fn: func(tb testing.TB) {
+				test.ErrorAs[*inputError](tb, nil)
+			},
+			wantFail: true,
+		},
+		{
+			name: "ErrorAs/fail with context",
+			fn: func(tb testing.TB) {
+				test.ErrorAs[*inputError](tb, &outputError{msg: "nope"}, test.Context("Expected an inputError"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "ErrorAs/fail with title",
+			fn: func(tb testing.TB) {
+				test.ErrorAs[*inputError](tb, &outputError{msg: "nope"}, test.Title("Type mismatch"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "WantErr/pass error",
+			fn: func(tb testing.TB) {
+				test.WantErr(tb, errors.New("bang"), true) // Wanted an error and got one - should pass
+			},
+			wantFail: false,
+		},
+		{
+			name: "WantErr/pass nil",
+			fn: func(tb testing.TB) {
+				test.WantErr(tb, nil, false) // Didn't want an error and got nil - should pass
+			},
+			wantFail: false,
+		},
+		{
+			name: "WantErr/fail error",
+			fn: func(tb testing.TB) {
+				test.WantErr(tb, errors.New("bang"), false) // Got an error but didn't want one - should fail
+			},
+			wantFail: true,
+		},
+		{
+			name: "WantErr/fail nil",
+			fn: func(tb testing.TB) {
+				test.WantErr(tb, nil, true) // Didn't get an error but wanted one - should fail
+			},
+			wantFail: true,
+		},
+		{
+			name: "WantErr/fail with context",
+			fn: func(tb testing.TB) {
+				test.WantErr(tb, errors.New("bang"), false, test.Context("Errors are bad!"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "WantErr/fail with title",
+			fn: func(tb testing.TB) {
+				test.WantErr(tb, errors.New("bang"), false, test.Title("A very bad test"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "WantErrIs/pass nil",
+			fn: func(tb testing.TB) {
+				test.WantErrIs(tb, nil, nil)
+			},
+			wantFail: false,
+		},
+		{
+			name: "WantErrIs/pass wrapped",
+			fn: func(tb testing.TB) {
+				test.WantErrIs(tb, fmt.Errorf("read: %w", io.EOF), io.EOF)
+			},
+			wantFail: false,
+		},
+		{
+			name: "WantErrIs/fail unexpected",
+			fn: func(tb testing.TB) {
+				test.WantErrIs(tb, io.EOF, nil)
+			},
+			wantFail: true,
+		},
+		{
+			name: "WantErrIs/fail missing",
+			fn: func(tb testing.TB) {
+				test.WantErrIs(tb, nil, io.EOF)
+			},
+			wantFail: true,
+		},
+		{
+			name: "WantErrIs/fail wrong error",
+			fn: func(tb testing.TB) {
+				test.WantErrIs(tb, fmt.Errorf("read: %w", io.ErrUnexpectedEOF), io.EOF)
+			},
+			wantFail: true,
+		},
+		{
+			name: "WantErrAs/pass nil",
+			fn: func(tb testing.TB) {
+				got := test.WantErrAs[*inputError](tb, nil, false)
+				if got != nil {
+					tb.Fatal("WantErrAs returned a non-nil error when none was wanted")
+				}
+			},
+			wantFail: false,
+		},
+		{
+			name: "WantErrAs/pass wrapped",
+			fn: func(tb testing.TB) {
+				inner := &inputError{msg: "boom"}
+
+				got := test.WantErrAs[*inputError](tb, fmt.Errorf("while frobnicating: %w", inner), true)
+				if got != inner {
+					tb.Fatal("WantErrAs did not return the wrapped error")
+				}
+			},
+			wantFail: false,
+		},
+		{
+			name: "WantErrAs/fail unexpected",
+			fn: func(tb testing.TB) {
+				test.WantErrAs[*inputError](tb, &inputError{msg: "boom"}, false)
+			},
+			wantFail: true,
+		},
+		{
+			name: "WantErrAs/fail missing",
+			fn: func(tb testing.TB) {
+				test.WantErrAs[*inputError](tb, nil, true)
+			},
+			wantFail: true,
+		},
+		{
+			name: "WantErrAs/fail wrong type",
+			fn: func(tb testing.TB) {
+				test.WantErrAs[*inputError](tb, &outputError{msg: "nope"}, true)
+			},
+			wantFail: true,
+		},
+		{
+			name: "That/pass",
+			fn: func(tb testing.TB) {
+				test.That(tb, 42, test.EqualTo(42))
+			},
+			wantFail: false,
+		},
+		{
+			name: "That/fail",
+			fn: func(tb testing.TB) {
+				test.That(tb, 5, test.MatchFunc("even", isEven))
+			},
+			wantFail: true,
+		},
+		{
+			name: "That/fail with context",
+			fn: func(tb testing.TB) {
+				test.That(tb, 42, test.Not(test.EqualTo(42)), test.Context("the answer is forbidden"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "That/pass all of",
+			fn: func(tb testing.TB) {
+				test.That(tb, 4, test.AllOf(test.MatchFunc("even", isEven), test.Not(test.EqualTo(2))))
+			},
+			wantFail: false,
+		},
+		{
+			name: "That/fail all of",
+			fn: func(tb testing.TB) {
+				test.That(tb, 5, test.AllOf(test.MatchFunc("even", isEven), test.Not(test.EqualTo(5)), test.Not(test.EqualTo(3))))
+			},
+			wantFail: true,
+		},
+		{
+			name: "That/pass any of",
+			fn: func(tb testing.TB) {
+				test.That(tb, 3, test.AnyOf(test.EqualTo(1), test.EqualTo(3)))
+			},
+			wantFail: false,
+		},
+		{
+			name: "That/fail any of",
+			fn: func(tb testing.TB) {
+				test.That(tb, 5, test.AnyOf(test.EqualTo(1), test.AllOf(test.MatchFunc("even", isEven), test.EqualTo(3))))
+			},
+			wantFail: true,
+		},
+		{
+			name: "That/pass each",
+			fn: func(tb testing.TB) {
+				test.That(tb, []int{2, 4, 6}, test.Each(test.MatchFunc("even", isEven)))
+			},
+			wantFail: false,
+		},
+		{
+			name: "That/fail each",
+			fn: func(tb testing.TB) {
+				test.That(tb, []int{2, 3, 4, 5}, test.Each(test.MatchFunc("even", isEven)))
+			},
+			wantFail: true,
+		},
+		{
+			name: "That/fail has field",
+			fn: func(tb testing.TB) {
+				errs := []*inputError{{msg: "bad"}, {msg: "worse"}}
+				message := func(err *inputError) string { return err.msg }
+				test.That(tb, errs, test.Each(test.HasField("msg", message, test.EqualTo("bad"))))
+			},
+			wantFail: true,
+		},
+		{
+			name: "That/pass nearly equal",
+			fn: func(tb testing.TB) {
+				test.That(tb, 3.0000000001, test.NearlyEqualTo(3.0))
+			},
+			wantFail: false,
+		},
+		{
+			name: "That/fail nearly equal",
+			fn: func(tb testing.TB) {
+				test.That(tb, 3.1, test.NearlyEqualTo(3.0, test.RelativeTolerance(0.01)))
+			},
+			wantFail: true,
+		},
+		{
+			name: "That/fail nearly equal bad option",
+			fn: func(tb testing.TB) {
+				test.That(tb, 3.0, test.NearlyEqualTo(3.0, test.RelativeTolerance(-1)))
+			},
+			wantFail: true,
+		},
+		{
+			name: "That/pass is error",
+			fn: func(tb testing.TB) {
+				test.That(tb, fmt.Errorf("read: %w", io.EOF), test.IsError(io.EOF))
+			},
+			wantFail: false,
+		},
+		{
+			name: "That/fail is error",
+			fn: func(tb testing.TB) {
+				test.That(tb, fmt.Errorf("read: %w", io.ErrUnexpectedEOF), test.AnyOf(test.IsError(io.EOF), test.IsError(io.ErrClosedPipe)))
+			},
+			wantFail: true,
+		},
+		{
+			name: "That/fail is error nil",
+			fn: func(tb testing.TB) {
+				test.That(tb, nil, test.IsError(io.EOF))
+			},
+			wantFail: true,
+		},
+		{
+			name: "Fail/basic",
+			fn: func(tb testing.TB) {
+				test.Fail(tb, test.Failure{Got: 3, Want: 4})
+			},
+			wantFail: true,
+		},
+		{
+			name: "Fail/full",
+			fn: func(tb testing.TB) {
+				test.Fail(tb, test.Failure{
+					Title:  "Invalid Order",
+					Got:    12.50,
+					Want:   15.00,
+					Reason: "Order total does not equal the sum of its items",
+					Sections: []test.Section{
+						{Label: "Items", Body: "apples: 5.00\noranges: 10.00"},
+						{Label: "Discounts", Body: "SPRING: -2.50\n"},
+					},
+				}, test.Context("checkout flow"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "Fail/with title",
+			fn: func(tb testing.TB) {
+				test.Fail(tb, test.Failure{Title: "Invalid Order", Got: "a", Want: "b"}, test.Title("Overridden"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "Fail/reason only",
+			fn: func(tb testing.TB) {
+				test.Fail(tb, test.Failure{
+					Title:    "Invalid Order",
+					Reason:   "Order has no items",
+					Sections: []test.Section{{Label: "Order", Body: "ID: 1234"}},
+				})
+			},
+			wantFail: true,
+		},
+		{
+			name: "Fail/bad option",
+			fn: func(tb testing.TB) {
+				test.Fail(tb, test.Failure{Got: "a", Want: "b"}, test.Title(""))
+			},
+			wantFail: true,
+		},
+		{
+			name: "True/pass",
+			fn: func(tb testing.TB) {
+				test.True(tb, true)
+			},
+			wantFail: false,
+		},
+		{
+			name: "True/fail",
+			fn: func(tb testing.TB) {
+				test.True(tb, false)
+			},
+			wantFail: true,
+		},
+		{
+			name: "True/fail with context",
+			fn: func(tb testing.TB) {
+				test.True(tb, false, test.Context("must always be true"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "True/fail with title",
+			fn: func(tb testing.TB) {
+				test.True(tb, false, test.Title("Argh!"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "False/pass",
+			fn: func(tb testing.TB) {
+				test.False(tb, false)
+			},
+			wantFail: false,
+		},
+		{
+			name: "False/fail",
+			fn: func(tb testing.TB) {
+				test.False(tb, true)
+			},
+			wantFail: true,
+		},
+		{
+			name: "False/fail with context",
+			fn: func(tb testing.TB) {
+				test.False(tb, true, test.Context("must always be false"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "False/fail with title",
+			fn: func(tb testing.TB) {
+				test.False(tb, true, test.Title("Argh!"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "Diff/pass",
+			fn: func(tb testing.TB) {
+				got := "Some\nstuff here in this file\nlines as well wow\nsome more stuff\n"
+				want := "Some\nstuff here in this file\nlines as well wow\nsome more stuff\n"
+
+				test.Diff(tb, got, want)
+			},
+			wantFail: false,
+		},
+		{
+			name: "Diff/pass no trailing newline",
+			fn: func(tb testing.TB) {
+				got := "Some\nstuff here in this file\nlines as well wow\nsome more stuff"
+				want := "Some\nstuff here in this file\nlines as well wow\nsome more stuff"
+
+				test.Diff(tb, got, want)
+			},
+			wantFail: false,
+		},
+		{
+			name: "Diff/fail",
+			fn: func(tb testing.TB) {
+				got := "Some\nstuff here in this file\nlines as well wow\nsome more stuff\n"
+				want := "Some\ndifferent stuff here in this file\nthis line is different\nsome more stuff\n"
+				test.Diff(tb, got, want)
+			},
+			wantFail: true,
+		},
+		{
+			name: "Diff/fail no trailing newline",
+			fn: func(tb testing.TB) {
+				got := "Some\nstuff here in this file\nlines as well wow\nsome more stuff"
+				want := "Some\ndifferent stuff here in this file\nthis line is different\nsome more stuff"
+				test.Diff(tb, got, want)
+			},
+			wantFail: true,
+		},
+		{
+			name: "DiffBytes/pass",
+			fn: func(tb testing.TB) {
+				got := []byte("Some\nstuff here in this file\nlines as well wow\nsome more stuff\n")
+				want := []byte("Some\nstuff here in this file\nlines as well wow\nsome more stuff\n")
+
+				test.DiffBytes(tb, got, want)
+			},
+			wantFail: false,
+		},
+		{
+			name: "DiffBytes/fail",
+			fn: func(tb testing.TB) {
+				got := []byte("Some\nstuff here in this file\nlines as well wow\nsome more stuff\n")
+				want := []byte("Some\ndifferent stuff here in this file\nthis line is different\nsome more stuff\n")
+				test.DiffBytes(tb, got, want)
+			},
+			wantFail: true,
+		},
+		{
+			name: "DiffBytes/fail context lines",
+			fn: func(tb testing.TB) {
+				got := []byte("Some\nstuff here in this file\nlines as well wow\nsome more stuff\n")
+				want := []byte("Some\ndifferent stuff here in this file\nthis line is different\nsome more stuff\n")
+				test.DiffBytes(tb, got, want, test.DiffContextLines(0))
+			},
+			wantFail: true,
+		},
+		{
+			name: "DiffBytes/fail max hunks",
+			fn: func(tb testing.TB) {
+				got, want := manyHunks(4)
+				test.DiffBytes(tb, got, want, test.MaxDiffHunks(2))
+			},
+			wantFail: true,
+		},
+		{
+			name: "DiffBytes/fail max hunks one omitted",
+			fn: func(tb testing.TB) {
+				got, want := manyHunks(2)
+				test.DiffBytes(tb, got, want, test.MaxDiffHunks(1))
+			},
+			wantFail: true,
+		},
+		{
+			name: "DiffBytes/fail max lines",
+			fn: func(tb testing.TB) {
+				got, want := manyHunks(4)
+				test.DiffBytes(tb, got, want, test.MaxDiffLines(14))
+			},
+			wantFail: true,
+		},
+		{
+			name: "DiffBytes/fail max lines last hunk",
+			fn: func(tb testing.TB) {
+				got, want := manyHunks(1)
+				test.DiffBytes(tb, got, want, test.MaxDiffLines(6))
+			},
+			wantFail: true,
+		},
+		{
+			name: "DiffBytes/fail limits not reached",
+			fn: func(tb testing.TB) {
+				got, want := manyHunks(2)
+				test.DiffBytes(tb, got, want, test.MaxDiffHunks(5), test.MaxDiffLines(100))
+			},
+			wantFail: true,
+		},
+		{
+			name: "DiffBytes/pass binary",
+			fn: func(tb testing.TB) {
+				test.DiffBytes(tb, []byte{0x00, 0xff, 0x10}, []byte{0x00, 0xff, 0x10})
+			},
+			wantFail: false,
+		},
+		{
+			name: "DiffBytes/fail binary",
+			fn: func(tb testing.TB) {
+				want := binaryData(100)
+				got := slices.Clone(want)
+				got[20] = 0xde
+				got[21] = 0xad
+				got[90] = 'X'
+
+				test.DiffBytes(tb, got, want)
+			},
+			wantFail: true,
+		},
+		{
+			name: "DiffBytes/fail binary different lengths",
+			fn: func(tb testing.TB) {
+				want := binaryData(40)
+				got := append(binaryData(40), []byte("more")...)
+
+				test.DiffBytes(tb, got, want)
+			},
+			wantFail: true,
+		},
+		{
+			name: "DiffBytes/fail binary one side",
+			fn: func(tb testing.TB) {
+				test.DiffBytes(tb, []byte("hello\x00world"), []byte("hello world"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "DiffBytes/fail binary max lines",
+			fn: func(tb testing.TB) {
+				want := binaryData(200)
+				got := slices.Clone(want)
+				got[20] = 0xde
+				got[180] = 0xad
+
+				test.DiffBytes(tb, got, want, test.MaxDiffLines(4))
+			},
+			wantFail: true,
+		},
+		{
+			name: "DiffReader/pass",
+			fn: func(tb testing.TB) {
+				got := []byte("Some\nstuff here in this file\nlines as well wow\nsome more stuff\n")
+				want := []byte("Some\nstuff here in this file\nlines as well wow\nsome more stuff\n")
+
+				test.DiffReader(tb, bytes.NewReader(got), bytes.NewReader(want))
+			},
+			wantFail: false,
+		},
+		{
+			name: "DiffReader/fail",
+			fn: func(tb testing.TB) {
+				got := []byte("Some\nstuff here in this file\nlines as well wow\nsome more stuff\n")
+				want := []byte("Some\ndifferent stuff here in this file\nthis line is different\nsome more stuff\n")
+
+				test.DiffReader(tb, bytes.NewReader(got), bytes.NewReader(want))
+			},
+			wantFail: true,
+		},
+		{
+			name: "Diff/fail with title",
+			fn: func(tb testing.TB) {
+				got := "Some\nstuff here in this file\nlines as well wow\nsome more stuff\n"
+				want := "Some\ndifferent stuff here in this file\nthis line is different\nsome more stuff\n"
+				test.Diff(tb, got, want, test.Title("File drift"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "Diff/fail with context",
+			fn: func(tb testing.TB) {
+				got := "Some\nstuff here in this file\nlines as well wow\nsome more stuff\n"
+				want := "Some\ndifferent stuff here in this file\nthis line is different\nsome more stuff\n"
+				test.Diff(tb, got, want, test.Context("config file drifted from checked-in copy"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "Diff/pass normalize",
+			fn: func(tb testing.TB) {
+				test.Diff(tb, "one\r\ntwo\r\n", "one\ntwo\n", test.Normalize(crlf))
+			},
+			wantFail: false,
+		},
+		{
+			name: "Diff/fail normalize",
+			fn: func(tb testing.TB) {
+				test.Diff(tb, "one\r\ntwo\r\n", "one\nthree\n", test.Normalize(crlf))
+			},
+			wantFail: true,
+		},
+		{
+			name: "DiffLines/pass",
+			fn: func(tb testing.TB) {
+				test.DiffLines(tb, []string{"one", "two", "three"}, []string{"one", "two", "three"})
+			},
+			wantFail: false,
+		},
+		{
+			name: "DiffLines/pass empty",
+			fn: func(tb testing.TB) {
+				test.DiffLines(tb, nil, []string{})
+			},
+			wantFail: false,
+		},
+		{
+			name: "DiffLines/fail",
+			fn: func(tb testing.TB) {
+				got := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}
+				want := []string{"a", "b", "c", "d", "E", "f", "g", "h", "i", "j", "k"}
+				test.DiffLines(tb, got, want)
+			},
+			wantFail: true,
+		},
+		{
+			name: "DiffLines/fail insertion",
+			fn: func(tb testing.TB) {
+				got := []string{"a", "b", "new", "c"}
+				want := []string{"a", "b", "c"}
+				test.DiffLines(tb, got, want, test.DiffContextLines(0))
+			},
+			wantFail: true,
+		},
+		{
+			name: "DiffLines/fail embedded newline",
+			fn: func(tb testing.TB) {
+				got := []string{"one", "two\nthree"}
+				want := []string{"one", "two", "three"}
+				test.DiffLines(tb, got, want)
+			},
+			wantFail: true,
+		},
+		{
+			name: "DiffLines/fail control character",
+			fn: func(tb testing.TB) {
+				got := []string{"one", "\x1b[31mtwo\x1b[0m"}
+				want := []string{"one", "two"}
+				test.DiffLines(tb, got, want)
+			},
+			wantFail: true,
+		},
+		{
+			name: "DiffLines/fail tab",
+			fn: func(tb testing.TB) {
+				got := []string{"name\tage", "alice\t30"}
+				want := []string{"name\tage", "alice\t31"}
+				test.DiffLines(tb, got, want)
+			},
+			wantFail: true,
+		},
+		{
+			name: "DiffLines/fail with title",
+			fn: func(tb testing.TB) {
+				test.DiffLines(tb, []string{"id,name", "1,apple"}, []string{"id,name", "1,banana"}, test.Title("CSV rows"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "DiffFS/pass",
+			fn: func(tb testing.TB) {
+				test.DiffFS(tb, fruitFS(), fruitFS())
+			},
+			wantFail: false,
+		},
+		{
+			name: "DiffFS/pass ignore paths",
+			fn: func(tb testing.TB) {
+				got := fruitFS()
+				got["build/output.log"] = &fstest.MapFile{Data: []byte("noise\n")}
+				got["debug.log"] = &fstest.MapFile{Data: []byte("noise\n")}
+
+				test.DiffFS(tb, got, fruitFS(), test.IgnorePaths("build", "*.log"))
+			},
+			wantFail: false,
+		},
+		{
+			name: "DiffFS/pass ignore nested paths",
+			fn: func(tb testing.TB) {
+				got := fruitFS()
+				got["fruit/notes/debug.log"] = &fstest.MapFile{Data: []byte("noise\n")}
+				got["fruit/tmp/scratch.txt"] = &fstest.MapFile{Data: []byte("noise\n")}
+
+				test.DiffFS(tb, got, fruitFS(), test.IgnorePaths("*.log", "tmp"))
 			},
 			wantFail: false,
 		},
 		{
-			name: "False/fail",
+			name: "DiffFS/fail ignore full path",
 			fn: func(tb testing.TB) {
-				test.False(tb, true)
+				got := fruitFS()
+				got["fruit/debug.log"] = &fstest.MapFile{Data: []byte("noise\n")}
+				got["fruit/notes/debug.log"] = &fstest.MapFile{Data: []byte("noise\n")}
+
+				// Patterns with a slash match the whole path, so only fruit/debug.log is ignored
+				test.DiffFS(tb, got, fruitFS(), test.IgnorePaths("fruit/*.log"))
 			},
 			wantFail: true,
 		},
 		{
-			name: "False/fail with context",
+			name: "DiffFS/pass normalize",
 			fn: func(tb testing.TB) {
-				test.False(tb, true, test.Context("must always be false"))
+				got := fruitFS()
+				got["fruit/apple.txt"] = &fstest.MapFile{Data: []byte("crunchy\r\nred\r\n")}
+
+				test.DiffFS(tb, got, fruitFS(), test.Normalize(crlf))
+			},
+			wantFail: false,
+		},
+		{
+			name: "DiffFS/fail",
+			fn: func(tb testing.TB) {
+				got := fruitFS()
+				delete(got, "fruit/banana.txt")
+				got["fruit/cherry.txt"] = &fstest.MapFile{Data: []byte("small\n")}
+				got["fruit/apple.txt"] = &fstest.MapFile{Data: []byte("crunchy\ngreen\n")}
+				got["bin/juice"].Mode = 0o644
+
+				test.DiffFS(tb, got, fruitFS())
 			},
 			wantFail: true,
 		},
 		{
-			name: "False/fail with title",
+			name: "DiffFS/fail binary",
 			fn: func(tb testing.TB) {
-				test.False(tb, true, test.Title("Argh!"))
+				got := fruitFS()
+				got["bin/juice"].Data = []byte{0x7f, 'E', 'L', 'F', 0x00, 0x02}
+
+				test.DiffFS(tb, got, fruitFS())
 			},
 			wantFail: true,
 		},
 		{
-			name: "Diff/pass",
+			name: "DiffFS/fail max diff hunks",
 			fn: func(tb testing.TB) {
-				got := "Some\nstuff here in this file\nlines as well wow\nsome more stuff\n"
-				want := "Some\nstuff here in this file\nlines as well wow\nsome more stuff\n"
+				got := fruitFS()
+				got["README.md"] = &fstest.MapFile{Data: []byte("# Vegetables\n")}
+				got["fruit/apple.txt"] = &fstest.MapFile{Data: []byte("crunchy\ngreen\n")}
+				got["fruit/banana.txt"] = &fstest.MapFile{Data: []byte("soft\nbrown\n")}
 
-				test.Diff(tb, got, want)
+				test.DiffFS(tb, got, fruitFS(), test.MaxDiffHunks(1))
 			},
-			wantFail: false,
+			wantFail: true,
 		},
 		{
-			name: "Diff/pass no trailing newline",
+			name: "DiffFS/fail with context",
 			fn: func(tb testing.TB) {
-				got := "Some\nstuff here in this file\nlines as well wow\nsome more stuff"
-				want := "Some\nstuff here in this file\nlines as well wow\nsome more stuff"
+				got := fruitFS()
+				delete(got, "README.md")
 
-				test.Diff(tb, got, want)
+				test.DiffFS(tb, got, fruitFS(), test.Context("generator output drifted"))
 			},
-			wantFail: false,
+			wantFail: true,
 		},
 		{
-			name: "Diff/fail",
+			name: "Option errors/Title empty",
 			fn: func(tb testing.TB) {
-				got := "Some\nstuff here in this file\nlines as well wow\nsome more stuff\n"
-				want := "Some\ndifferent stuff here in this file\nthis line is different\nsome more stuff\n"
-				test.Diff(tb, got, want)
+				test.Equal(tb, 1, 1, test.Title(""))
 			},
 			wantFail: true,
 		},
 		{
-			name: "Diff/fail no trailing newline",
+			name: "Option errors/Context empty",
 			fn: func(tb testing.TB) {
-				got := "Some\nstuff here in this file\nlines as well wow\nsome more stuff"
-				want := "Some\ndifferent stuff here in this file\nthis line is different\nsome more stuff"
-				test.Diff(tb, got, want)
+				test.Equal(tb, 1, 1, test.Context(""))
 			},
 			wantFail: true,
 		},
 		{
-			name: "DiffBytes/pass",
+			name: "Option errors/Context format resolves empty",
 			fn: func(tb testing.TB) {
-				got := []byte("Some\nstuff here in this file\nlines as well wow\nsome more stuff\n")
-				want := []byte("Some\nstuff here in this file\nlines as well wow\nsome more stuff\n")
-
-				test.DiffBytes(tb, got, want)
+				test.Equal(tb, 1, 1, test.Context("%s", ""))
 			},
-			wantFail: false,
+			wantFail: true,
 		},
 		{
-			name: "DiffBytes/fail",
+			name: "Option errors/DiffContextLines negative",
 			fn: func(tb testing.TB) {
-				got := []byte("Some\nstuff here in this file\nlines as well wow\nsome more stuff\n")
-				want := []byte("Some\ndifferent stuff here in this file\nthis line is different\nsome more stuff\n")
-				test.DiffBytes(tb, got, want)
+				test.Diff(tb, "a", "b", test.DiffContextLines(-1))
 			},
 			wantFail: true,
 		},
 		{
-			name: "DiffReader/pass",
+			name: "Option errors/MaxDiffHunks zero",
 			fn: func(tb testing.TB) {
-				got := []byte("Some\nstuff here in this file\nlines as well wow\nsome more stuff\n")
-				want := []byte("Some\nstuff here in this file\nlines as well wow\nsome more stuff\n")
-
-				test.DiffReader(tb, bytes.NewReader(got), bytes.NewReader(want))
+				test.Diff(tb, "a", "b", test.MaxDiffHunks(0))
 			},
-			wantFail: false,
+			wantFail: true,
 		},
 		{
-			name: "DiffReader/fail",
+			name: "Option errors/MaxDiffLines zero",
 			fn: func(tb testing.TB) {
-				got := []byte("Some\nstuff here in this file\nlines as well wow\nsome more stuff\n")
-				want := []byte("Some\ndifferent stuff here in this file\nthis line is different\nsome more stuff\n")
-
-				test.DiffReader(tb, bytes.NewReader(got), bytes.NewReader(want))
+				test.Diff(tb, "a", "b", test.MaxDiffLines(0))
 			},
 			wantFail: true,
 		},
 		{
-			name: "Diff/fail with title",
+			name: "Option errors/SaveDiff empty",
 			fn: func(tb testing.TB) {
-				got := "Some\nstuff here in this file\nlines as well wow\nsome more stuff\n"
-				want := "Some\ndifferent stuff here in this file\nthis line is different\nsome more stuff\n"
-				test.Diff(tb, got, want, test.Title("File drift"))
+				test.Diff(tb, "a", "b", test.SaveDiff(""))
 			},
 			wantFail: true,
 		},
 		{
-			name: "Diff/fail with context",
+			name: "Option errors/SaveDiff not local",
 			fn: func(tb testing.TB) {
-				got := "Some\nstuff here in this file\nlines as well wow\nsome more stuff\n"
-				want := "Some\ndifferent stuff here in this file\nthis line is different\nsome more stuff\n"
-				test.Diff(tb, got, want, test.Context("config file drifted from checked-in copy"))
+				test.Diff(tb, "a", "b", test.SaveDiff("../escape.diff"))
 			},
 			wantFail: true,
 		},
 		{
-			name: "Option errors/Title empty",
+			name: "Option errors/Normalize nil",
 			fn: func(tb testing.TB) {
-				test.Equal(tb, 1, 1, test.Title(""))
+				test.Diff(tb, "a", "b", test.Normalize(nil))
 			},
 			wantFail: true,
 		},
 		{
-			name: "Option errors/Context empty",
+			name: "Option errors/IgnorePaths none",
 			fn: func(tb testing.TB) {
-				test.Equal(tb, 1, 1, test.Context(""))
+				test.DiffFS(tb, fruitFS(), fruitFS(), test.IgnorePaths())
 			},
 			wantFail: true,
 		},
 		{
-			name: "Option errors/Context format resolves empty",
+			name: "Option errors/IgnorePaths bad pattern",
 			fn: func(tb testing.TB) {
-				test.Equal(tb, 1, 1, test.Context("%s", ""))
+				test.DiffFS(tb, fruitFS(), fruitFS(), test.IgnorePaths("[oops"))
+			},
+			wantFail: true,
+		},
+		{
+			name: "Option errors/RelativeTolerance negative",
+			fn: func(tb testing.TB) {
+				test.NearlyEqual(tb, 1.0, 1.0, test.RelativeTolerance(-1e-9))
+			},
+			wantFail: true,
+		},
+		{
+			name: "Option errors/RelativeTolerance NaN",
+			fn: func(tb testing.TB) {
+				test.NearlyEqual(tb, 1.0, 1.0, test.RelativeTolerance(math.NaN()))
+			},
+			wantFail: true,
+		},
+		{
+			name: "Option errors/ULPTolerance negative",
+			fn: func(tb testing.TB) {
+				test.NearlyEqual(tb, 1.0, 1.0, test.ULPTolerance(-1))
+			},
+			wantFail: true,
+		},
+		{
+			name: "Option errors/AbsOrRelTolerance absolute infinity",
+			fn: func(tb testing.TB) {
+				test.NearlyEqual(tb, 1.0, 1.0, test.AbsOrRelTolerance(math.Inf(1), 1e-9))
+			},
+			wantFail: true,
+		},
+		{
+			name: "Option errors/AbsOrRelTolerance relative negative",
+			fn: func(tb testing.TB) {
+				test.NearlyEqual(tb, 1.0, 1.0, test.AbsOrRelTolerance(1e-12, -1))
 			},
 			wantFail: true,
 		},
@@ -720,6 +2570,360 @@ func TestCapture(t *testing.T) {
 	})
 }
 
+func TestSaveDiff(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tb := &TB{out: buf, artifactDir: t.TempDir()}
+
+	got, want := manyHunks(3)
+	test.DiffBytes(tb, got, want, test.SaveDiff("nested/big.diff"))
+
+	test.True(t, tb.failed)
+
+	path := filepath.Join(tb.artifactDir, "nested", "big.diff")
+	saved, err := os.ReadFile(path)
+	test.Ok(t, err)
+
+	test.True(t, strings.Contains(buf.String(), "Diff written to "+path), test.Context("output: %s", buf.String()))
+	test.True(t, strings.HasPrefix(string(saved), "diff want got\n"), test.Context("saved: %s", saved))
+	test.Equal(t, strings.Count(string(saved), "\n@@ "), 3) // One header per hunk
+}
+
+func TestGolden(t *testing.T) {
+	if *update {
+		t.Skip("golden files are rewritten when -update is set")
+	}
+
+	t.Run("match", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "match.golden")
+		test.Ok(t, os.WriteFile(path, []byte("hello\n"), 0o644))
+
+		buf := &bytes.Buffer{}
+		tb := &TB{out: buf}
+
+		test.Golden(tb, []byte("hello\n"), path)
+
+		test.False(t, tb.failed)
+		test.Equal(t, buf.String(), "")
+	})
+
+	t.Run("trailing newline", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "newline.golden")
+		test.Ok(t, os.WriteFile(path, []byte("hello"), 0o644))
+
+		buf := &bytes.Buffer{}
+		tb := &TB{out: buf}
+
+		test.Golden(tb, []byte("hello\n"), path)
+
+		test.True(t, tb.failed)
+		test.True(
+			t,
+			strings.Contains(buf.String(), "got ends in a newline but the golden file does not"),
+			test.Context("output: %s", buf.String()),
+		)
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "mismatch.golden")
+		test.Ok(t, os.WriteFile(path, []byte("hello\nthere\n"), 0o644))
+
+		buf := &bytes.Buffer{}
+		tb := &TB{out: buf}
+
+		test.Golden(tb, []byte("hello\neveryone\n"), path)
+
+		test.True(t, tb.failed)
+		test.True(t, strings.Contains(buf.String(), "- there\n"), test.Context("output: %s", buf.String()))
+		test.True(t, strings.Contains(buf.String(), "+ everyone\n"), test.Context("output: %s", buf.String()))
+		test.True(
+			t,
+			strings.Contains(buf.String(), "Hint: If this change is expected, re-run with "+test.GoldenUpdateEnv+"=1"),
+			test.Context("output: %s", buf.String()),
+		)
+		test.True(
+			t,
+			strings.Contains(buf.String(), "Because: got does not match golden file "+path),
+			test.Context("output: %s", buf.String()),
+		)
+
+		// Golden file should be left alone
+		contents, err := os.ReadFile(path)
+		test.Ok(t, err)
+		test.Equal(t, string(contents), "hello\nthere\n")
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "nested", "missing.golden")
+
+		buf := &bytes.Buffer{}
+		tb := &TB{out: buf}
+
+		test.Golden(tb, []byte("brand new\n"), path)
+
+		test.False(t, tb.failed)
+		test.Equal(t, buf.String(), "Golden: created "+path)
+
+		contents, err := os.ReadFile(path)
+		test.Ok(t, err)
+		test.Equal(t, string(contents), "brand new\n")
+	})
+
+	t.Run("update", func(t *testing.T) {
+		t.Setenv(test.GoldenUpdateEnv, "true")
+
+		path := filepath.Join(t.TempDir(), "update.golden")
+		test.Ok(t, os.WriteFile(path, []byte("old\n"), 0o644))
+
+		buf := &bytes.Buffer{}
+		tb := &TB{out: buf}
+
+		test.Golden(tb, []byte("new\n"), path)
+
+		test.False(t, tb.failed)
+		test.Equal(t, buf.String(), "Golden: updated "+path)
+
+		contents, err := os.ReadFile(path)
+		test.Ok(t, err)
+		test.Equal(t, string(contents), "new\n")
+	})
+
+	t.Run("update unchanged", func(t *testing.T) {
+		t.Setenv(test.GoldenUpdateEnv, "1")
+
+		path := filepath.Join(t.TempDir(), "unchanged.golden")
+		test.Ok(t, os.WriteFile(path, []byte("same\n"), 0o644))
+
+		buf := &bytes.Buffer{}
+		tb := &TB{out: buf}
+
+		test.Golden(tb, []byte("same\n"), path)
+
+		test.False(t, tb.failed)
+		test.Equal(t, buf.String(), "") // Nothing written, nothing reported
+	})
+}
+
+func TestDefaults(t *testing.T) {
+	t.Run("per test", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		tb := &TB{TB: t, out: buf}
+
+		test.Defaults(tb, test.FloatEqualityThreshold(0.1))
+		test.Defaults(tb, test.Context("from defaults"))
+
+		test.NearlyEqual(tb, 1.05, 1.0)
+		test.False(t, tb.failed, test.Context("output: %s", buf.String()))
+
+		test.Equal(tb, 1, 2)
+		test.True(t, tb.failed)
+		test.True(t, strings.Contains(buf.String(), "(from defaults)"), test.Context("output: %s", buf.String()))
+	})
+
+	t.Run("per call wins", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		tb := &TB{TB: t, out: buf}
+
+		test.Defaults(tb, test.With("source", "defaults"))
+		test.Equal(tb, 1, 2, test.With("source", "call"))
+
+		test.True(t, strings.Contains(buf.String(), `source: "call"`), test.Context("output: %s", buf.String()))
+		test.False(t, strings.Contains(buf.String(), `"defaults"`), test.Context("output: %s", buf.String()))
+	})
+
+	t.Run("removed on cleanup", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+
+		var tb *TB
+
+		t.Run("inner", func(t *testing.T) {
+			tb = &TB{TB: t, out: buf}
+			test.Defaults(tb, test.Context("from defaults"))
+		})
+
+		test.Equal(tb, 1, 2)
+		test.True(t, tb.failed)
+		test.False(t, strings.Contains(buf.String(), "(from defaults)"), test.Context("output: %s", buf.String()))
+	})
+
+	t.Run("inherited by subtests", func(t *testing.T) {
+		test.Defaults(t, test.Context("from parent"))
+
+		t.Run("inner", func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			tb := &TB{TB: t, out: buf}
+
+			test.Defaults(tb, test.With("source", "inner"))
+			test.Equal(tb, 1, 2)
+
+			test.True(t, tb.failed)
+			test.True(t, strings.Contains(buf.String(), "(from parent)"), test.Context("output: %s", buf.String()))
+			test.True(t, strings.Contains(buf.String(), `source: "inner"`), test.Context("output: %s", buf.String()))
+		})
+	})
+
+	t.Run("unhashable tb", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		tb := valueTB{TB: &TB{TB: t, out: buf}, logs: []string{}}
+
+		test.Equal(tb, 1, 1) // Must not panic looking up defaults
+
+		test.Defaults(tb, test.Context("from defaults"))
+		test.Equal(tb, 1, 2)
+
+		test.True(t, tb.failed)
+		test.True(t, strings.Contains(buf.String(), "(from defaults)"), test.Context("output: %s", buf.String()))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		tb := &TB{TB: t, out: buf}
+
+		test.Defaults(tb, test.Title(""))
+		test.True(t, tb.failed)
+		test.Equal(t, buf.String(), "Defaults: could not apply options: cannot set title to an empty string")
+	})
+
+	t.Run("global", func(t *testing.T) {
+		test.Ok(t, test.SetDefaults(test.FloatEqualityThreshold(0.1)))
+		t.Cleanup(func() { test.Ok(t, test.SetDefaults()) })
+
+		buf := &bytes.Buffer{}
+		tb := &TB{out: buf}
+
+		test.NearlyEqual(tb, 1.05, 1.0)
+		test.False(t, tb.failed, test.Context("output: %s", buf.String()))
+
+		test.NearlyEqual(tb, 1.05, 1.0, test.FloatEqualityThreshold(0.01))
+		test.True(t, tb.failed)
+	})
+
+	t.Run("global formatter", func(t *testing.T) {
+		test.Ok(t, test.SetDefaults(test.FormatWith(formatAddress)))
+		t.Cleanup(func() { test.Ok(t, test.SetDefaults()) })
+
+		buf := &bytes.Buffer{}
+		tb := &TB{out: buf}
+
+		test.Equal(tb, address{City: "London"}, address{City: "Leeds"})
+		test.True(t, strings.Contains(buf.String(), "Got:\taddress in London"), test.Context("output: %s", buf.String()))
+
+		buf.Reset()
+		test.That(tb, address{City: "London"}, test.EqualTo(address{City: "Leeds"}))
+		test.True(t, strings.Contains(buf.String(), "address in Leeds"), test.Context("output: %s", buf.String()))
+
+		buf.Reset()
+		test.Equal(tb, address{City: "London"}, address{City: "Leeds"}, test.FormatWith(func(a address) string {
+			return "per call " + a.City
+		}))
+		test.True(t, strings.Contains(buf.String(), "Got:\tper call London"), test.Context("output: %s", buf.String()))
+	})
+
+	t.Run("global invalid", func(t *testing.T) {
+		err := test.SetDefaults(test.RelativeTolerance(-1))
+		test.Err(t, err)
+	})
+}
+
+func TestMonotonicTime(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tb := &TB{out: buf}
+
+	now := time.Now() // Has a monotonic clock reading, which would show as m=+0.000001 with %v
+	test.Equal(tb, now, now.Add(time.Second))
+
+	test.True(t, tb.failed)
+	test.False(t, strings.Contains(buf.String(), "m="), test.Context("output: %s", buf.String()))
+
+	t.Run("nested", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		tb := &TB{out: buf}
+
+		type event struct{ At time.Time }
+
+		now := time.Now()
+		test.Equal(tb, event{At: now}, event{At: now.Add(time.Second)})
+
+		test.True(t, tb.failed)
+		test.False(t, strings.Contains(buf.String(), "m="), test.Context("output: %s", buf.String()))
+	})
+}
+
+func TestCyclicValue(t *testing.T) {
+	type node struct {
+		Value int
+		Next  *node
+	}
+
+	buf := &bytes.Buffer{}
+	tb := &TB{out: buf}
+
+	got := &node{Value: 1}
+	got.Next = got
+
+	test.Equal(tb, got, &node{Value: 2})
+
+	test.True(t, tb.failed)
+
+	// The cycle is shown once, then by address where it refers back to itself
+	want := fmt.Sprintf("&{Value: 1 Next: %p}", got)
+	test.True(t, strings.Contains(buf.String(), want), test.Context("output: %s", buf.String()))
+}
+
+// valueTB is a testing.TB passed by value that cannot be used as a map key.
+type valueTB struct {
+	*TB
+
+	logs []string
+}
+
+// compareFold compares strings case insensitively.
+func compareFold(a, b string) int {
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// fruitFS returns a small file tree used to exercise test.DiffFS.
+func fruitFS() fstest.MapFS {
+	return fstest.MapFS{
+		"README.md":        &fstest.MapFile{Data: []byte("# Fruit\n")},
+		"fruit/apple.txt":  &fstest.MapFile{Data: []byte("crunchy\nred\n")},
+		"fruit/banana.txt": &fstest.MapFile{Data: []byte("soft\nyellow\n")},
+		"bin/juice":        &fstest.MapFile{Data: []byte("#!/bin/sh\necho juice\n"), Mode: 0o755},
+	}
+}
+
+// crlf normalizes Windows line endings.
+func crlf(data []byte) []byte {
+	return bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+}
+
+// binaryData returns n bytes of deterministic, non UTF-8 data.
+func binaryData(n int) []byte {
+	data := make([]byte, n)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+
+	return data
+}
+
+// manyHunks returns got and want inputs that differ in exactly n places, far
+// enough apart that each difference is its own hunk in a diff.
+func manyHunks(n int) (got, want []byte) {
+	var g, w strings.Builder
+
+	for i := range n * 10 {
+		fmt.Fprintf(&w, "line %d\n", i)
+
+		if i%10 == 5 {
+			fmt.Fprintf(&g, "changed line %d\n", i)
+		} else {
+			fmt.Fprintf(&g, "line %d\n", i)
+		}
+	}
+
+	return []byte(g.String()), []byte(w.String())
+}
+
 // inputError is a concrete error type used to exercise test.ErrorAs.
 type inputError struct{ msg string }
 
@@ -730,3 +2934,39 @@ func (e *inputError) Error() string { return e.msg }
 type outputError struct{ msg string }
 
 func (e *outputError) Error() string { return e.msg }
+
+// typedNilError returns a nil *inputError as an error, which is not itself nil.
+func typedNilError() error {
+	var err *inputError
+
+	return err
+}
+
+// isEven reports whether n is even, used to exercise test.MatchFunc.
+func isEven(n int) bool {
+	return n%2 == 0
+}
+
+// formatAddress formats an address by its city alone, used to exercise test.FormatWith.
+func formatAddress(a address) string {
+	return "address in " + a.City
+}
+
+// celsius is a temperature that only implements fmt.GoStringer.
+type celsius float64
+
+func (c celsius) GoString() string { return fmt.Sprintf("%.1f°C", float64(c)) }
+
+// person is a nested struct used to exercise pretty printing of values.
+type person struct {
+	Name    string
+	Email   string
+	Address address
+}
+
+// address is nested inside person.
+type address struct {
+	Street   string
+	City     string
+	Postcode string
+}
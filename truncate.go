@@ -0,0 +1,172 @@
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// maxValueWidth is the length in bytes beyond which a formatted value is truncated
+	// to a window of this width around the first difference.
+	maxValueWidth = 120
+
+	// truncateLead is the number of bytes shown before the first difference in a
+	// truncated value, so the difference is seen in context.
+	truncateLead = 40
+)
+
+// writeValues writes the "Got:" and "Wanted:" lines for the already formatted got and want
+// to s. If either is too long to read comfortably on one line, both are cut down to the same
+// window around their first difference, which is marked with a caret.
+func writeValues(s *strings.Builder, got, want string) {
+	if len(got) <= maxValueWidth && len(want) <= maxValueWidth ||
+		strings.Contains(got, "\n") || strings.Contains(want, "\n") {
+		// Multi-line values have been pretty printed and are readable as they are
+		fmt.Fprintf(s, "Got:\t%s\n", got)
+		fmt.Fprintf(s, "Wanted:\t%s\n", want)
+
+		return
+	}
+
+	first := firstDifference([]byte(got), []byte(want))
+	start := max(0, first-truncateLead)
+
+	gotWindow, column := truncate(got, start, first)
+	wantWindow, _ := truncate(want, start, first)
+
+	fmt.Fprintf(s, "Got:\t%s\n", gotWindow)
+	fmt.Fprintf(s, "Wanted:\t%s\n", wantWindow)
+
+	if first != -1 {
+		fmt.Fprintf(s, "\t%s^\n", strings.Repeat(" ", column))
+	}
+}
+
+// truncateValue returns text cut down to maxValueWidth bytes if it is longer, keeping the
+// byte at offset focus in view with truncateLead bytes before it, see [truncate]. If focus
+// is at or beyond the end of text, the end of it is kept in view instead.
+func truncateValue(text string, focus int) string {
+	if len(text) <= maxValueWidth {
+		return text
+	}
+
+	start := max(0, focus-truncateLead)
+	if focus >= len(text) {
+		start = len(text) - maxValueWidth
+	}
+
+	window, _ := truncate(text, start, -1)
+
+	return window
+}
+
+// truncate returns the maxValueWidth bytes of text from start, with an ellipsis in place of
+// anything cut from the beginning and a count of anything cut from the end. It also returns the
+// column at which the byte at offset first is shown, for placing a caret under it.
+//
+// The window is widened as needed so that it never splits a multi-byte rune.
+func truncate(text string, start, first int) (window string, column int) {
+	start = min(start, len(text))
+	for start > 0 && !utf8.RuneStart(text[start]) {
+		start--
+	}
+
+	end := min(start+maxValueWidth, len(text))
+	for end < len(text) && !utf8.RuneStart(text[end]) {
+		end++
+	}
+
+	s := &strings.Builder{}
+
+	if start > 0 {
+		s.WriteString("…")
+
+		column = 1
+	}
+
+	s.WriteString(text[start:end])
+
+	if first >= start {
+		// The difference may be part way through a rune, the caret goes under the whole rune
+		first = min(first, end)
+		for first > start && first < len(text) && !utf8.RuneStart(text[first]) {
+			first--
+		}
+
+		column += utf8.RuneCountInString(text[start:first])
+	}
+
+	if end < len(text) {
+		fmt.Fprintf(s, " … %s", plural(len(text)-end, "more byte"))
+	}
+
+	return s.String(), column
+}
+
+// multiLineDiff returns the unified diff between got and want if they are both strings or
+// byte slices spanning multiple lines and either is too long to show on one line, or nil
+// if they should be shown as normal.
+//
+// If always is set, they are shown as a diff if either spans multiple lines whatever their
+// length, and if they are equal there is no diff so the text they share is shown instead.
+func multiLineDiff(got, want any, cfg config, always bool) []byte {
+	gotData, ok := stringBytes(got)
+	if !ok {
+		return nil
+	}
+
+	wantData, ok := stringBytes(want)
+	if !ok {
+		return nil
+	}
+
+	gotLines := bytes.Contains(gotData, []byte("\n"))
+	wantLines := bytes.Contains(wantData, []byte("\n"))
+
+	switch {
+	case always && !gotLines && !wantLines:
+		return nil
+	case !always && (!gotLines || !wantLines):
+		return nil
+	case !always && len(gotData) <= maxValueWidth && len(wantData) <= maxValueWidth:
+		return nil
+	}
+
+	// The diff is shown in the failure rather than saved, so must be rendered for the terminal,
+	// and the values were compared exactly so normalising them could hide the difference
+	cfg.saveDiff = ""
+	cfg.normalizers = nil
+
+	if rendered := renderDiff("want", "got", gotData, wantData, cfg); rendered != nil {
+		return rendered
+	}
+
+	if !bytes.Equal(gotData, wantData) {
+		// Differing only in a trailing newline, which a diff doesn't show, so show them as normal
+		return nil
+	}
+
+	return fmt.Appendf(nil, "Got and Wanted are both:\n%s", fixNL(gotData))
+}
+
+// stringBytes returns the contents of v if its underlying type is a string or byte slice,
+// reporting whether it was. Text that has already been formatted is never treated as a string.
+func stringBytes(v any) ([]byte, bool) {
+	if _, ok := v.(verbatim); ok {
+		return nil, false
+	}
+
+	value := reflect.ValueOf(v)
+
+	switch {
+	case value.Kind() == reflect.String:
+		return []byte(value.String()), true
+	case value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Uint8:
+		return value.Bytes(), true
+	default:
+		return nil, false
+	}
+}